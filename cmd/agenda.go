@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// TodayCmd lists your open tasks due today, with overdue ones first
+type TodayCmd struct {
+	JSON bool `short:"j" help:"Output as JSON"`
+}
+
+func (c *TodayCmd) Run(client *api.Client, g *Globals) error {
+	return printAgenda(client, g, false, c.JSON)
+}
+
+// WeekCmd lists your open tasks due in the coming week, with overdue ones
+// first
+type WeekCmd struct {
+	JSON bool `short:"j" help:"Output as JSON"`
+}
+
+func (c *WeekCmd) Run(client *api.Client, g *Globals) error {
+	return printAgenda(client, g, true, c.JSON)
+}
+
+// agendaFields are the columns of the agenda tables
+var agendaFields = []string{"gid", "name", "due", "project"}
+
+// printAgenda shows your overdue tasks, those due today and, with week,
+// those due in the next seven days, each under its own header
+func printAgenda(client *api.Client, g *Globals, week bool, asJSON bool) error {
+	me, err := client.MyGID()
+	if err != nil {
+		return err
+	}
+
+	dues := []string{"overdue", "today"}
+	if week {
+		dues = append(dues, "week")
+	}
+
+	results := make([][]api.Task, len(dues))
+	fetches := make([]func(ctx context.Context) error, len(dues))
+	for i, due := range dues {
+		i, due := i, due
+		fetches[i] = func(ctx context.Context) (err error) {
+			results[i], err = client.WithContext(ctx).ListTasks(api.TaskListOptions{
+				Assignee:        me,
+				Due:             due,
+				IncludeSubtasks: true,
+				SortBy:          "due_date",
+				OptFields:       api.TaskListOptFields + "," + priorityOptFields,
+			}) // no limit: an agenda should be complete
+			return err
+		}
+	}
+	if err := runParallel(fetches...); err != nil {
+		return err
+	}
+
+	// A task shows up once, in the first group it matches
+	seen := map[string]bool{}
+	for i, tasks := range results {
+		var kept []api.Task
+		for _, t := range tasks {
+			if !seen[t.GID] {
+				seen[t.GID] = true
+				kept = append(kept, t)
+			}
+		}
+		sortAgenda(kept)
+		results[i] = kept
+	}
+
+	if asJSON {
+		out := map[string][]api.Task{}
+		for i, due := range dues {
+			out[due] = results[i]
+			if out[due] == nil {
+				out[due] = []api.Task{}
+			}
+		}
+		return printJSON(out)
+	}
+
+	headers := map[string]string{
+		"overdue": "Overdue",
+		"today":   "Due today",
+		"week":    "Later this week",
+	}
+	var groups []boardColumn
+	for i, due := range dues {
+		if len(results[i]) > 0 {
+			groups = append(groups, boardColumn{name: headers[due], tasks: results[i]})
+		}
+	}
+
+	if len(groups) == 0 {
+		if week {
+			fmt.Println("Nothing due this week.")
+		} else {
+			fmt.Println("Nothing due today.")
+		}
+		return nil
+	}
+	return printTaskGroups(g, groups, agendaFields, nil)
+}
+
+// priorityOptFields are the custom field details priorityRank needs
+const priorityOptFields = "custom_fields.name,custom_fields.resource_subtype,custom_fields.enum_value.name,custom_fields.enum_options.name"
+
+// sortAgenda orders tasks by due date, tasks due on the same day by
+// priority, and tasks of the same priority by name
+func sortAgenda(tasks []api.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.DueOn != b.DueOn {
+			return a.DueOn < b.DueOn
+		}
+		if pa, pb := priorityRank(a), priorityRank(b); pa != pb {
+			return pa < pb
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+}
+
+// priorityRank returns the position of a task's value of its "Priority"
+// enum custom field among the field's options, so that the first option
+// (usually the highest priority) ranks first. Tasks without a priority
+// rank after all others.
+func priorityRank(t api.Task) int {
+	for _, f := range t.CustomFields {
+		if !strings.EqualFold(f.Name, "priority") || f.EnumValue == nil {
+			continue
+		}
+		for i, o := range f.EnumOptions {
+			if o.GID == f.EnumValue.GID {
+				return i
+			}
+		}
+	}
+	return math.MaxInt
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+func TestSortAgenda(t *testing.T) {
+	options := []api.EnumOption{{GID: "h", Name: "High"}, {GID: "m", Name: "Medium"}, {GID: "l", Name: "Low"}}
+	task := func(name, due, priority string) api.Task {
+		t := api.Task{Name: name, DueOn: due}
+		if priority != "" {
+			t.CustomFields = []api.CustomField{{
+				Name:        "Priority",
+				EnumValue:   &api.EnumOption{GID: priority},
+				EnumOptions: options,
+			}}
+		}
+		return t
+	}
+
+	tasks := []api.Task{
+		task("a low", "2024-05-01", "l"),
+		task("tomorrow", "2024-05-02", "h"),
+		task("b none", "2024-05-01", ""),
+		task("c high", "2024-05-01", "h"),
+		task("a none", "2024-05-01", ""),
+		task("b high", "2024-05-01", "h"),
+	}
+	sortAgenda(tasks)
+
+	var got []string
+	for _, t := range tasks {
+		got = append(got, t.Name)
+	}
+	want := []string{"b high", "c high", "a low", "a none", "b none", "tomorrow"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortAgenda order = %q, want %q", got, want)
+	}
+}
@@ -2,9 +2,10 @@ package cmd
 
 import (
 	"fmt"
+	"io/fs"
+	"net/url"
 	"os"
 	"path/filepath"
-	"text/tabwriter"
 
 	"github.com/mauricejumelet/asana-cli/internal/api"
 )
@@ -12,20 +13,25 @@ import (
 type AttachmentsCmd struct {
 	List     AttachmentsListCmd     `cmd:"" help:"List attachments on a task"`
 	Get      AttachmentsGetCmd      `cmd:"" help:"Get attachment details"`
-	Upload   AttachmentsUploadCmd   `cmd:"" help:"Upload a file to a task"`
+	Upload   AttachmentsUploadCmd   `cmd:"" help:"Upload files to a task"`
+	Link     AttachmentsLinkCmd     `cmd:"" help:"Attach a link to a task"`
 	Download AttachmentsDownloadCmd `cmd:"" help:"Download an attachment"`
 	Delete   AttachmentsDeleteCmd   `cmd:"" help:"Delete an attachment"`
 }
 
 type AttachmentsListCmd struct {
-	TaskGID string `arg:"" help:"Task GID to list attachments for"`
+	TaskGID string `arg:"" optional:"" help:"Task GID to list attachments for"`
 	JSON    bool   `short:"j" help:"Output as JSON"`
 }
 
-func (c *AttachmentsListCmd) Run(client *api.Client) error {
+func (c *AttachmentsListCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
 	attachments, err := client.ListAttachments(c.TaskGID)
 	if err != nil {
-		return err
+		return notFound(err, "task", c.TaskGID)
 	}
 
 	if c.JSON {
@@ -37,15 +43,10 @@ func (c *AttachmentsListCmd) Run(client *api.Client) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "GID\tNAME\tSIZE\tCREATED\tHOST")
-	fmt.Fprintln(w, "---\t----\t----\t-------\t----")
+	t := newTable("GID", "NAME", "SIZE", "CREATED", "HOST")
 
 	for _, a := range attachments {
-		created := "-"
-		if a.CreatedAt != "" && len(a.CreatedAt) >= 10 {
-			created = a.CreatedAt[:10]
-		}
+		created := orDash(datePart(a.CreatedAt))
 
 		size := "-"
 		if a.Size > 0 {
@@ -58,11 +59,10 @@ func (c *AttachmentsListCmd) Run(client *api.Client) error {
 		}
 
 		name := truncate(a.Name, 50)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", a.GID, name, size, created, host)
+		t.row(a.GID, name, size, created, host)
 	}
 
-	w.Flush()
-	return nil
+	return t.print(g)
 }
 
 type AttachmentsGetCmd struct {
@@ -73,7 +73,7 @@ type AttachmentsGetCmd struct {
 func (c *AttachmentsGetCmd) Run(client *api.Client) error {
 	attachment, err := client.GetAttachment(c.AttachmentGID)
 	if err != nil {
-		return err
+		return notFound(err, "attachment", c.AttachmentGID)
 	}
 
 	if c.JSON {
@@ -93,7 +93,7 @@ func (c *AttachmentsGetCmd) Run(client *api.Client) error {
 		fmt.Printf("Size: %s\n", formatSize(attachment.Size))
 	}
 	if attachment.CreatedAt != "" {
-		fmt.Printf("Created: %s\n", attachment.CreatedAt)
+		fmt.Printf("Created: %s\n", formatTime(attachment.CreatedAt))
 	}
 	if attachment.Parent != nil {
 		fmt.Printf("Parent: %s (%s)\n", attachment.Parent.Name, attachment.Parent.GID)
@@ -112,37 +112,159 @@ func (c *AttachmentsGetCmd) Run(client *api.Client) error {
 }
 
 type AttachmentsUploadCmd struct {
-	TaskGID  string `arg:"" help:"Task GID to attach file to"`
-	FilePath string `arg:"" help:"Path to file to upload" type:"path"`
-	JSON     bool   `short:"j" help:"Output as JSON"`
+	TaskGID     string   `arg:"" help:"Task GID to attach files to"`
+	FilePaths   []string `arg:"" help:"Files to upload" type:"path"`
+	Recursive   bool     `short:"r" help:"Upload every file inside directory arguments"`
+	Concurrency int      `default:"3" help:"Number of files to upload at the same time"`
+	JSON        bool     `short:"j" help:"Output as JSON"`
+}
+
+// uploadResult is the outcome of uploading one file
+type uploadResult struct {
+	File       string          `json:"file"`
+	Attachment *api.Attachment `json:"attachment,omitempty"`
+	Error      string          `json:"error,omitempty"`
 }
 
-func (c *AttachmentsUploadCmd) Run(client *api.Client) error {
-	attachment, err := client.UploadAttachment(c.TaskGID, c.FilePath)
+func (c *AttachmentsUploadCmd) Run(client *api.Client, g *Globals) error {
+	files, err := c.collectFiles()
 	if err != nil {
 		return err
 	}
+	if len(files) == 0 {
+		return fmt.Errorf("no files to upload")
+	}
+
+	results := runPool(len(files), c.Concurrency, func(i int) uploadResult {
+		r := uploadResult{File: files[i]}
+		attachment, err := client.UploadAttachment(c.TaskGID, files[i])
+		if err != nil {
+			r.Error = err.Error()
+		} else {
+			r.Attachment = attachment
+		}
+		return r
+	})
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
 
 	if c.JSON {
-		return printJSON(attachment)
+		// A single upload keeps printing just the attachment, as it always has
+		var out interface{} = results
+		if len(results) == 1 && failed == 0 {
+			out = results[0].Attachment
+		}
+		if err := printJSON(out); err != nil {
+			return err
+		}
+	} else if g.Quiet {
+		for _, r := range results {
+			gid := ""
+			if r.Attachment != nil {
+				gid = r.Attachment.GID
+			}
+			printQuietResult(r.File, gid, r.Error)
+		}
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Printf("FAILED %s: %s\n", r.File, r.Error)
+			} else {
+				fmt.Printf("Uploaded %s (GID: %s)\n", r.File, r.Attachment.GID)
+			}
+		}
+		if len(results) > 1 {
+			fmt.Printf("\n%d uploaded, %d failed\n", len(results)-failed, failed)
+		}
 	}
 
-	fmt.Printf("File uploaded successfully!\n")
-	fmt.Printf("GID: %s\n", attachment.GID)
-	fmt.Printf("Name: %s\n", attachment.Name)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed", failed, len(results))
+	}
+	return nil
+}
+
+// AttachmentsLinkCmd attaches a URL instead of uploading a file, for Google
+// Docs, pull requests, designs and the like
+type AttachmentsLinkCmd struct {
+	TaskGID string `arg:"" help:"Task GID to attach the link to"`
+	URL     string `arg:"" help:"URL to attach"`
+	Name    string `short:"n" help:"Name shown for the link (default: the URL)"`
+	JSON    bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *AttachmentsLinkCmd) Run(client *api.Client, g *Globals) error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q (must start with http:// or https://)", c.URL)
+	}
 
+	attachment, err := client.AttachURL(c.TaskGID, c.URL, c.Name)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if c.JSON {
+		return printJSON(attachment)
+	}
+	if g.Quiet {
+		fmt.Println(attachment.GID)
+		return nil
+	}
+
+	fmt.Printf("Attached %s (GID: %s)\n", attachment.Name, attachment.GID)
 	return nil
 }
 
+// collectFiles expands the path arguments, walking directories when
+// --recursive is set
+func (c *AttachmentsUploadCmd) collectFiles() ([]string, error) {
+	var files []string
+	for _, path := range c.FilePaths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		if !c.Recursive {
+			return nil, fmt.Errorf("%s is a directory (use --recursive to upload its files)", path)
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.Type().IsRegular() {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
 type AttachmentsDownloadCmd struct {
 	AttachmentGID string `arg:"" help:"Attachment GID to download"`
 	Output        string `short:"o" help:"Output file path (defaults to current directory with attachment name)"`
+	Resume        bool   `help:"Continue a partial download (kept as <output>.part when a download fails)" xor:"existing"`
+	Overwrite     bool   `help:"Replace the output file if it exists" xor:"existing"`
 }
 
 func (c *AttachmentsDownloadCmd) Run(client *api.Client) error {
 	attachment, err := client.GetAttachment(c.AttachmentGID)
 	if err != nil {
-		return err
+		return notFound(err, "attachment", c.AttachmentGID)
 	}
 
 	destPath := c.Output
@@ -150,7 +272,27 @@ func (c *AttachmentsDownloadCmd) Run(client *api.Client) error {
 		destPath = filepath.Join(".", attachment.Name)
 	}
 
-	if err := client.DownloadAttachment(attachment, destPath); err != nil {
+	if !c.Resume && !c.Overwrite {
+		if _, err := os.Stat(destPath); err == nil {
+			return fmt.Errorf("%s already exists (use --overwrite to replace it)", destPath)
+		}
+	}
+
+	opts := api.DownloadOptions{Resume: c.Resume}
+	var bar *progressBar
+	if isTerminal(os.Stdout) {
+		bar = newProgressBar(os.Stderr, attachment.Name)
+		opts.Progress = bar.update
+	}
+
+	err = client.DownloadAttachment(attachment, destPath, opts)
+	if bar != nil {
+		bar.done()
+	}
+	if err != nil {
+		if _, statErr := os.Stat(destPath + ".part"); statErr == nil {
+			return fmt.Errorf("%w (continue with --resume)", err)
+		}
 		return err
 	}
 
@@ -160,22 +302,16 @@ func (c *AttachmentsDownloadCmd) Run(client *api.Client) error {
 
 type AttachmentsDeleteCmd struct {
 	AttachmentGID string `arg:"" help:"Attachment GID to delete"`
-	Force         bool   `short:"f" help:"Skip confirmation"`
+	Force         bool   `short:"f" help:"Skip confirmation (same as --yes)"`
 }
 
-func (c *AttachmentsDeleteCmd) Run(client *api.Client) error {
-	if !c.Force {
-		fmt.Printf("Are you sure you want to delete attachment %s? [y/N] ", c.AttachmentGID)
-		var response string
-		fmt.Scanln(&response)
-		if response != "y" && response != "Y" {
-			fmt.Println("Cancelled.")
-			return nil
-		}
+func (c *AttachmentsDeleteCmd) Run(client *api.Client, g *Globals) error {
+	if !c.Force && !g.confirm(fmt.Sprintf("Are you sure you want to delete attachment %s?", c.AttachmentGID)) {
+		return nil
 	}
 
 	if err := client.DeleteAttachment(c.AttachmentGID); err != nil {
-		return err
+		return notFound(err, "attachment", c.AttachmentGID)
 	}
 
 	fmt.Printf("Attachment %s deleted.\n", c.AttachmentGID)
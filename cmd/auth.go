@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+	"github.com/mauricejumelet/asana-cli/internal/config"
+)
+
+type AuthCmd struct {
+	Check AuthCheckCmd `cmd:"" help:"Verify that ASANA_TOKEN is valid"`
+}
+
+type AuthCheckCmd struct {
+	All  bool `help:"Check every profile in the TOML or YAML config files instead"`
+	JSON bool `short:"j" help:"Output as JSON (with --all)"`
+}
+
+// Run checks the configured token. With --all, main runs it without a
+// client, as each profile gets its own.
+func (c *AuthCheckCmd) Run(client *api.Client, g *Globals) error {
+	if c.All {
+		return checkProfiles(g, c.JSON)
+	}
+	if c.JSON {
+		return fmt.Errorf("--json only applies to --all")
+	}
+
+	user, err := client.Verify()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Authenticated as %s", user.Name)
+	if user.Email != "" {
+		fmt.Printf(" <%s>", user.Email)
+	}
+	fmt.Println()
+	return nil
+}
+
+// profileCheck is the result of checking one profile's credentials
+type profileCheck struct {
+	Profile   string    `json:"profile"`
+	File      string    `json:"file"`
+	User      *api.User `json:"user,omitempty"`
+	Workspace string    `json:"workspace,omitempty"`
+	OK        bool      `json:"ok"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// checkProfiles verifies the token of every configured profile and fails
+// if any of them is rejected. Profiles are loaded one after another, as a
+// token_command may prompt to unlock a password manager, and then verified
+// in parallel.
+func checkProfiles(g *Globals, asJSON bool) error {
+	profiles, err := config.Profiles(g.Config)
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		return fmt.Errorf("no profiles found (profiles are defined under profiles in a .toml or .yaml config file)")
+	}
+
+	checks := make([]profileCheck, len(profiles))
+	var fetches []func(ctx context.Context) error
+	for i, p := range profiles {
+		checks[i] = profileCheck{Profile: p.Name, File: p.File}
+		cfg, err := config.LoadProfile(p)
+		if err != nil {
+			checks[i].Error = err.Error()
+			continue
+		}
+		checks[i].Workspace = cfg.Workspace
+		if g.BaseURL != "" {
+			cfg.BaseURL = g.BaseURL
+		}
+
+		check := &checks[i]
+		fetches = append(fetches, func(context.Context) error {
+			user, err := api.NewClient(cfg).Verify()
+			if err != nil {
+				check.Error = err.Error()
+			} else {
+				check.User, check.OK = user, true
+			}
+			return nil // a failed profile doesn't stop the others
+		})
+	}
+	if err := runParallel(fetches...); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if !check.OK {
+			failed++
+		}
+	}
+
+	if asJSON {
+		if err := printJSON(checks); err != nil {
+			return err
+		}
+	} else {
+		t := newTable("PROFILE", "USER", "WORKSPACE", "STATUS")
+		for _, check := range checks {
+			user, status := "-", "OK"
+			if check.User != nil {
+				user = check.User.Name
+				if check.User.Email != "" {
+					user += " <" + check.User.Email + ">"
+				}
+			}
+			if !check.OK {
+				status = "error: " + strings.SplitN(check.Error, "\n", 2)[0]
+			}
+			t.row(check.Profile, user, orDash(check.Workspace), status)
+		}
+		if err := t.print(g); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d profiles failed the check", failed, len(checks))
+	}
+	return nil
+}
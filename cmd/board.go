@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// boardColumn is one section of a project board and its tasks
+type boardColumn struct {
+	name  string
+	tasks []api.Task
+}
+
+// printBoard shows a project's tasks grouped by section, side by side when
+// the columns fit the terminal and one section after another otherwise
+func printBoard(client *api.Client, g *Globals, project string, includeCompleted bool, limit, width int) error {
+	if width < 8 {
+		return fmt.Errorf("--width must be at least 8")
+	}
+
+	var sections []api.Section
+	var tasks []api.Task
+	err := runParallel(
+		func(ctx context.Context) error {
+			var err error
+			sections, err = client.WithContext(ctx).ListSections(project)
+			return err
+		},
+		func(ctx context.Context) error {
+			var err error
+			tasks, err = client.WithContext(ctx).GetProjectTasks(project, includeCompleted, limit, "gid,name,completed,memberships.project.gid,memberships.section.gid")
+			return err
+		},
+	)
+	if err != nil {
+		return notFound(err, "project", project)
+	}
+
+	columns := boardColumns(project, sections, tasks)
+	if len(columns) == 0 {
+		fmt.Println("No sections found.")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	cols, _ := terminalSize()
+	if cols > 0 && len(columns)*(width+3)-3 > cols {
+		renderBoardVertical(&buf, columns, cols)
+	} else {
+		renderBoard(&buf, columns, width)
+	}
+	if limit > 0 && len(tasks) >= limit {
+		fmt.Fprintf(&buf, "\n(Showing %d tasks, use -l to increase limit)\n", limit)
+	}
+	return pageOutput(g, buf.Bytes())
+}
+
+// boardColumns sorts tasks into the project's sections, keeping the
+// project order within each section
+func boardColumns(project string, sections []api.Section, tasks []api.Task) []boardColumn {
+	columns := make([]boardColumn, len(sections))
+	index := make(map[string]int, len(sections))
+	for i, s := range sections {
+		columns[i].name = s.Name
+		index[s.GID] = i
+	}
+
+	for _, t := range tasks {
+		for _, m := range t.Memberships {
+			if m.Project == nil || m.Project.GID != project || m.Section == nil {
+				continue
+			}
+			if i, ok := index[m.Section.GID]; ok {
+				columns[i].tasks = append(columns[i].tasks, t)
+			}
+			break
+		}
+	}
+
+	return columns
+}
+
+// renderBoard writes the columns side by side, each width characters wide
+func renderBoard(buf *bytes.Buffer, columns []boardColumn, width int) {
+	cells := make([][]string, len(columns))
+	height := 0
+	for i, col := range columns {
+		cells[i] = append(cells[i], truncate(fmt.Sprintf("%s (%d)", col.name, len(col.tasks)), width), strings.Repeat("-", width))
+		for _, t := range col.tasks {
+			cells[i] = append(cells[i], boardCard(t, width)...)
+		}
+		height = max(height, len(cells[i]))
+	}
+
+	for row := 0; row < height; row++ {
+		line := make([]string, len(cells))
+		for i := range cells {
+			cell := ""
+			if row < len(cells[i]) {
+				cell = cells[i][row]
+			}
+			line[i] = padRight(cell, width)
+		}
+		buf.WriteString(strings.TrimRight(strings.Join(line, " | "), " ") + "\n")
+	}
+}
+
+// renderBoardVertical writes one section after another, for terminals too
+// narrow to show the columns side by side
+func renderBoardVertical(buf *bytes.Buffer, columns []boardColumn, termWidth int) {
+	for i, col := range columns {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "%s (%d)\n", col.name, len(col.tasks))
+		for _, t := range col.tasks {
+			for _, line := range boardCard(t, max(termWidth-2, 8)) {
+				buf.WriteString("  " + line + "\n")
+			}
+		}
+	}
+}
+
+// boardCard returns a task's name wrapped to width, as a bulleted card
+func boardCard(t api.Task, width int) []string {
+	bullet := "- "
+	if t.Completed {
+		bullet = "x "
+	}
+
+	lines := wrapWords(t.Name, width-2)
+	for i := range lines {
+		if i == 0 {
+			lines[i] = bullet + lines[i]
+		} else {
+			lines[i] = "  " + lines[i]
+		}
+	}
+	return lines
+}
+
+// wrapWords splits s into lines of at most width columns, breaking
+// between words where possible
+func wrapWords(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for displayWidth(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			head, tail := cutWidth(word, width)
+			if head == "" {
+				// Not even one character fits: let it overflow rather
+				// than loop forever
+				_, n := utf8.DecodeRuneInString(word)
+				head, tail = word[:n], word[n:]
+			}
+			lines = append(lines, head)
+			word = tail
+		}
+		switch {
+		case line == "":
+			line = word
+		case displayWidth(line)+1+displayWidth(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
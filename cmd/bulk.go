@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// TasksBulkUpdateCmd applies a file of per-task changes, for migrations and
+// mass edits such as reassigning someone's tasks
+type TasksBulkUpdateCmd struct {
+	File        string `arg:"" help:"JSON file with a list of {\"gid\": ..., \"fields\": {...}} objects" type:"existingfile"`
+	DryRun      bool   `help:"Show what would be changed without changing anything"`
+	Concurrency int    `default:"3" help:"Number of tasks to update at the same time"`
+	JSON        bool   `short:"j" help:"Output as JSON"`
+}
+
+// bulkUpdate is one entry of a bulk-update file
+type bulkUpdate struct {
+	GID    string     `json:"gid"`
+	Fields bulkFields `json:"fields"`
+}
+
+// bulkFields are the fields a bulk update can change. An empty string
+// clears assignee, due_on and start_on; due dates accept the same formats
+// as --due and assignees the same references as --assignee.
+type bulkFields struct {
+	Name      *string `json:"name"`
+	Notes     *string `json:"notes"`
+	HTMLNotes *string `json:"html_notes"`
+	Assignee  *string `json:"assignee"`
+	DueOn     *string `json:"due_on"`
+	StartOn   *string `json:"start_on"`
+	Completed *bool   `json:"completed"`
+	Liked     *bool   `json:"liked"`
+}
+
+// bulkResult is the outcome of updating one task
+type bulkResult struct {
+	GID   string `json:"gid"`
+	Error string `json:"error,omitempty"`
+}
+
+func (c *TasksBulkUpdateCmd) Run(client *api.Client, g *Globals) error {
+	updates, err := c.readUpdates()
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		fmt.Println("No updates to apply.")
+		return nil
+	}
+
+	// Resolve every entry up front, so a bad assignee or date fails before
+	// anything is changed for that task and each user is looked up once
+	results := make([]bulkResult, len(updates))
+	opts := make([]api.UpdateTaskOptions, len(updates))
+	users := map[string]string{}
+	for i, u := range updates {
+		results[i].GID = u.GID
+		if opts[i], err = bulkOptions(client, u, users); err != nil {
+			results[i].Error = err.Error()
+		}
+	}
+
+	if !c.DryRun {
+		resolved := results
+		results = runPool(len(updates), c.Concurrency, func(i int) bulkResult {
+			r := resolved[i]
+			if r.Error != "" {
+				return r // already failed to resolve
+			}
+			if err := applyBulkUpdate(client, r.GID, opts[i]); err != nil {
+				r.Error = notFound(err, "task", r.GID).Error()
+			}
+			return r
+		})
+	}
+
+	var failed []string
+	for _, r := range results {
+		if r.Error != "" {
+			failed = append(failed, r.GID)
+		}
+	}
+
+	switch {
+	case c.JSON:
+		if err := printJSON(results); err != nil {
+			return err
+		}
+	case g.Quiet:
+		for _, r := range results {
+			printQuietResult(r.GID, r.GID, r.Error)
+		}
+	default:
+		for i, r := range results {
+			switch {
+			case r.Error != "":
+				fmt.Printf("FAILED %s: %s\n", r.GID, r.Error)
+			case c.DryRun:
+				fmt.Printf("Would update %s: %s\n", r.GID, strings.Join(updates[i].Fields.names(), ", "))
+			default:
+				fmt.Printf("Updated %s\n", r.GID)
+			}
+		}
+
+		verb := "Updated"
+		if c.DryRun {
+			verb = "Would update"
+		}
+		fmt.Printf("\n%s %d tasks, %d failed.\n", verb, len(results)-len(failed), len(failed))
+		if len(failed) > 0 {
+			fmt.Printf("Failed: %s\n", strings.Join(failed, ", "))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d of %d updates failed", len(failed), len(results))
+	}
+	return nil
+}
+
+func (c *TasksBulkUpdateCmd) readUpdates() ([]bulkUpdate, error) {
+	f, err := os.Open(c.File)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	// Unknown fields are rejected, so a typo can't silently do nothing
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	var updates []bulkUpdate
+	if err := dec.Decode(&updates); err != nil {
+		return nil, fmt.Errorf("parsing JSON: %w", err)
+	}
+
+	for i, u := range updates {
+		if !isGID(u.GID) {
+			return nil, fmt.Errorf("entry %d: invalid gid %q", i+1, u.GID)
+		}
+	}
+	return updates, nil
+}
+
+// bulkOptions turns an entry's fields into update options, resolving the
+// assignee and parsing dates. users caches resolved assignees.
+func bulkOptions(client *api.Client, u bulkUpdate, users map[string]string) (api.UpdateTaskOptions, error) {
+	f := u.Fields
+	opts := api.UpdateTaskOptions{
+		Name:      f.Name,
+		Notes:     f.Notes,
+		HTMLNotes: f.HTMLNotes,
+		Completed: f.Completed,
+		Liked:     f.Liked,
+	}
+
+	if f.Assignee != nil {
+		gid, ok := users[*f.Assignee]
+		if !ok {
+			var err error
+			if gid, err = resolveUser(client, *f.Assignee); err != nil {
+				return opts, err
+			}
+			users[*f.Assignee] = gid
+		}
+		opts.Assignee = &gid
+	}
+	if f.DueOn != nil {
+		due, err := parseDueDate(*f.DueOn, time.Now())
+		if err != nil {
+			return opts, fmt.Errorf("due_on: %w", err)
+		}
+		opts.DueOn = &due
+	}
+	if f.StartOn != nil {
+		start, err := parseDueDate(*f.StartOn, time.Now())
+		if err != nil {
+			return opts, fmt.Errorf("start_on: %w", err)
+		}
+		opts.StartOn = &start
+		if opts.DueOn != nil {
+			if err := checkStartDate(start, *opts.DueOn); err != nil {
+				return opts, err
+			}
+		}
+	}
+
+	if opts == (api.UpdateTaskOptions{}) {
+		return opts, fmt.Errorf("no fields to update")
+	}
+	return opts, nil
+}
+
+// applyBulkUpdate updates one task. A start date without a due date is
+// sent with the task's current due date (and time), which the API requires.
+func applyBulkUpdate(client *api.Client, gid string, opts api.UpdateTaskOptions) error {
+	if opts.StartOn != nil && opts.DueOn == nil {
+		task, err := client.GetTask(gid)
+		if err != nil {
+			return err
+		}
+		if err := checkStartDate(*opts.StartOn, task.DueOn); err != nil {
+			return err
+		}
+		keepCurrentDue(&opts, task)
+	}
+
+	_, err := client.UpdateTask(gid, opts)
+	return err
+}
+
+// names lists the fields that are set, for dry-run output
+func (f bulkFields) names() []string {
+	set := map[string]bool{
+		"name":       f.Name != nil,
+		"notes":      f.Notes != nil,
+		"html_notes": f.HTMLNotes != nil,
+		"assignee":   f.Assignee != nil,
+		"due_on":     f.DueOn != nil,
+		"start_on":   f.StartOn != nil,
+		"completed":  f.Completed != nil,
+		"liked":      f.Liked != nil,
+	}
+
+	var names []string
+	for name, ok := range set {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+type CacheCmd struct {
+	Clear CacheClearCmd `cmd:"" help:"Remove all cached API responses"`
+}
+
+type CacheClearCmd struct{}
+
+func (c *CacheClearCmd) Run() error {
+	if err := api.ClearCache(); err != nil {
+		return err
+	}
+
+	fmt.Println("Cache cleared.")
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI colors for chart bars
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+	defaultCols = 80
+)
+
+// useColor reports whether output may contain ANSI colors: stdout must be
+// a terminal and neither --no-color nor NO_COLOR may be set
+func useColor(g *Globals) bool {
+	return !g.NoColor && os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+}
+
+// bar is one labelled value in a bar chart
+type bar struct {
+	label string
+	value int
+	color string
+}
+
+// barChart writes a horizontal bar chart scaled so the largest value fills
+// the width left over by the labels and counts
+func barChart(buf *bytes.Buffer, bars []bar, cols int, color bool) {
+	labelWidth, maxValue := 0, 0
+	for _, b := range bars {
+		labelWidth = max(labelWidth, displayWidth(b.label))
+		maxValue = max(maxValue, b.value)
+	}
+	countWidth := len(fmt.Sprintf("%d", maxValue))
+	barWidth := max(cols-labelWidth-countWidth-4, 10)
+
+	for _, b := range bars {
+		pad := strings.Repeat(" ", labelWidth-displayWidth(b.label))
+		blocks := barBlocks(b.value, maxValue, barWidth)
+		fill := strings.Repeat(" ", barWidth-utf8.RuneCountInString(blocks))
+		if color && b.color != "" && blocks != "" {
+			blocks = b.color + blocks + colorReset
+		}
+		fmt.Fprintf(buf, "  %s%s %s%s %*d\n", b.label, pad, blocks, fill, countWidth, b.value)
+	}
+}
+
+// eighths are the partial block characters, from 1/8 to a full block
+var eighths = []rune("▏▎▍▌▋▊▉█")
+
+// barBlocks renders value as a bar of at most width characters, using
+// partial blocks for eighths of a character. Non-zero values always get at
+// least a sliver.
+func barBlocks(value, maxValue, width int) string {
+	if value <= 0 || maxValue <= 0 {
+		return ""
+	}
+	units := max(value*width*8/maxValue, 1)
+	bar := strings.Repeat(string(eighths[7]), units/8)
+	if units%8 > 0 {
+		bar += string(eighths[units%8-1])
+	}
+	return bar
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+	"github.com/mauricejumelet/asana-cli/internal/config"
+	"golang.org/x/term"
+)
+
+type ConfigCmd struct {
+	Init ConfigInitCmd `cmd:"" help:"Create a config file interactively"`
+}
+
+// ConfigInitCmd asks for a token and workspace and writes them to a config
+// file, so first-time setup doesn't need a hand-written .env. A --config path
+// ending in .toml, .yaml or .yml gets a file in that format.
+type ConfigInitCmd struct {
+	Force bool `short:"f" help:"Overwrite an existing config file"`
+}
+
+func (c *ConfigInitCmd) Run(g *Globals) error {
+	path := g.Config
+	if path == "" {
+		var err error
+		if path, err = config.DefaultConfigFile(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := os.Stat(path); err == nil && !c.Force {
+		return fmt.Errorf("%s already exists (use --force to overwrite it)", path)
+	}
+
+	in := bufio.NewReader(os.Stdin)
+
+	fmt.Println("Create a personal access token at https://app.asana.com/0/my-apps")
+	fmt.Print("Token: ")
+	token, err := readSecret(in)
+	fmt.Println()
+	token = strings.TrimSpace(token)
+	if token == "" {
+		if err != nil {
+			return fmt.Errorf("reading token: %w", err)
+		}
+		return fmt.Errorf("no token given")
+	}
+
+	baseURL := g.BaseURL
+	if baseURL == "" {
+		baseURL = os.Getenv("ASANA_BASE_URL")
+	}
+	client := api.NewClient(&config.Config{Token: token, BaseURL: baseURL})
+
+	workspaces, err := client.ListWorkspaces()
+	if err != nil {
+		return api.ExplainAuthError(err)
+	}
+	if len(workspaces) == 0 {
+		return fmt.Errorf("this token has no workspaces")
+	}
+
+	workspace := workspaces[0]
+	if len(workspaces) > 1 {
+		for i, w := range workspaces {
+			fmt.Printf("  %d) %s\n", i+1, w.Name)
+		}
+		fmt.Printf("Workspace [1-%d]: ", len(workspaces))
+		answer, _ := in.ReadString('\n')
+		n, err := strconv.Atoi(strings.TrimSpace(answer))
+		if err != nil || n < 1 || n > len(workspaces) {
+			return fmt.Errorf("invalid choice %q", strings.TrimSpace(answer))
+		}
+		workspace = workspaces[n-1]
+	}
+	fmt.Printf("Using workspace %s (%s)\n", workspace.Name, workspace.GID)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+	data := config.FileContents(path, token, workspace.GID)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(path, 0o600); err != nil {
+		return fmt.Errorf("writing config file: %w", err)
+	}
+
+	fmt.Printf("Config written to %s\n", path)
+	return nil
+}
+
+// readSecret reads a line from stdin without echoing it. When stdin is not
+// a terminal (e.g. a token piped in), the line is read from in as usual.
+func readSecret(in *bufio.Reader) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return in.ReadString('\n')
+	}
+	secret, err := term.ReadPassword(fd)
+	return string(secret), err
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// ConfirmFlags let a command preview its changes and ask before applying
+// them. Off by default, so scripts are never blocked on a prompt; the
+// global --yes shows the preview without asking.
+type ConfirmFlags struct {
+	Confirm bool `help:"Show the changes and ask before applying them"`
+}
+
+// fieldChange is a field's value before and after a change
+type fieldChange struct {
+	field  string
+	before string
+	after  string
+}
+
+// confirmChanges shows the fields that would change on task and asks
+// whether to go ahead. It returns false if the user declines or nothing
+// would change.
+func (f ConfirmFlags) confirmChanges(g *Globals, task *api.Task, changes []fieldChange) bool {
+	var changed []fieldChange
+	width := 0
+	for _, ch := range changes {
+		if ch.before != ch.after {
+			changed = append(changed, ch)
+			width = max(width, len(ch.field))
+		}
+	}
+
+	fmt.Printf("Task: %s [%s]\n", task.Name, task.GID)
+	if len(changed) == 0 {
+		fmt.Println("Nothing would change.")
+		return false
+	}
+	for _, ch := range changed {
+		fmt.Printf("  %-*s  %s -> %s\n", width+1, ch.field+":", ch.before, ch.after)
+	}
+
+	return g.confirm("Apply?")
+}
+
+// previewValue formats a field value for a change preview
+func previewValue(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return "-"
+	}
+	return truncate(s, 60)
+}
+
+// assigneeName is the name shown for a task's assignee in a preview
+func assigneeName(task *api.Task) string {
+	if task.Assignee == nil {
+		return "-"
+	}
+	return task.Assignee.Name
+}
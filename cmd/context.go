@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+	"github.com/mauricejumelet/asana-cli/internal/config"
+)
+
+// ContextCmd shows what the CLI is pointed at, so it can be checked before
+// running commands that change data
+type ContextCmd struct {
+	JSON bool `short:"j" help:"Output as JSON"`
+}
+
+type contextInfo struct {
+	User          *api.User `json:"user"`
+	Token         string    `json:"token"`
+	TokenCommand  string    `json:"token_command,omitempty"`
+	WorkspaceGID  string    `json:"workspace_gid"`
+	WorkspaceName string    `json:"workspace_name,omitempty"`
+	Organization  bool      `json:"workspace_is_organization"`
+	EmailDomains  []string  `json:"workspace_email_domains,omitempty"`
+	ConfigFiles   []string  `json:"config_files"`
+	Profile       string    `json:"profile,omitempty"`
+
+	DefaultProject  string `json:"default_project,omitempty"`
+	DefaultAssignee string `json:"default_assignee,omitempty"`
+}
+
+func (c *ContextCmd) Run(client *api.Client, g *Globals, cfg *config.Config) error {
+	user, err := client.Verify()
+	if err != nil {
+		return err
+	}
+
+	info := contextInfo{
+		User:         user,
+		Token:        tokenHint(cfg.Token),
+		TokenCommand: cfg.TokenCommand,
+		WorkspaceGID: client.Workspace(),
+		ConfigFiles:  cfg.Files,
+		Profile:      cfg.Profile,
+	}
+	if info.ConfigFiles == nil {
+		info.ConfigFiles = []string{}
+	}
+	info.DefaultProject, info.DefaultAssignee = g.defaults(cfg)
+
+	if info.WorkspaceGID != "" {
+		workspace, err := client.GetWorkspace(info.WorkspaceGID)
+		switch {
+		case err == nil:
+			info.WorkspaceName = workspace.Name
+			info.Organization = workspace.IsOrganization
+			info.EmailDomains = workspace.EmailDomains
+		case api.IsStatus(err, http.StatusForbidden), api.IsStatus(err, http.StatusNotFound):
+			// Reported below as not accessible
+		default:
+			return err
+		}
+	}
+
+	if c.JSON {
+		return printJSON(info)
+	}
+
+	fmt.Printf("User: %s", user.Name)
+	if user.Email != "" {
+		fmt.Printf(" <%s>", user.Email)
+	}
+	fmt.Println()
+	if info.TokenCommand != "" {
+		fmt.Printf("Token: %s (from %q)\n", info.Token, info.TokenCommand)
+	} else {
+		fmt.Printf("Token: %s\n", info.Token)
+	}
+
+	switch {
+	case info.WorkspaceGID == "":
+		fmt.Println("Workspace: (not set)")
+	case info.WorkspaceName == "":
+		fmt.Printf("Workspace: %s (not accessible to this user)\n", info.WorkspaceGID)
+	default:
+		kind := "workspace"
+		if info.Organization {
+			kind = "organization"
+		}
+		fmt.Printf("Workspace: %s (%s), %s\n", info.WorkspaceName, info.WorkspaceGID, kind)
+		if len(info.EmailDomains) > 0 {
+			fmt.Printf("Email domains: %s\n", strings.Join(info.EmailDomains, ", "))
+		}
+	}
+
+	if info.DefaultProject != "" {
+		fmt.Printf("Default project: %s\n", info.DefaultProject)
+	}
+	if info.DefaultAssignee != "" {
+		fmt.Printf("Default assignee: %s\n", info.DefaultAssignee)
+	}
+
+	if len(info.ConfigFiles) == 0 {
+		fmt.Println("Config files: (none, environment only)")
+	} else {
+		fmt.Printf("Config files: %s\n", strings.Join(info.ConfigFiles, ", "))
+	}
+	if info.Profile != "" {
+		fmt.Printf("Profile: %s\n", info.Profile)
+	}
+
+	return nil
+}
+
+// tokenHint shows only the last four characters of a token
+func tokenHint(token string) string {
+	if len(token) <= 4 {
+		return "****"
+	}
+	return "****" + token[len(token)-4:]
+}
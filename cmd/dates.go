@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // --tz must work on systems without a zoneinfo database (Windows)
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// parseDueDate turns a due date as typed by the user into the YYYY-MM-DD
+// form the API expects. Besides ISO dates it accepts "today", "tomorrow",
+// weekday names (the next occurrence after today) and offsets such as
+// "+3d" or "+2w", all relative to now in the local timezone.
+// An empty string is returned unchanged.
+func parseDueDate(s string, now time.Time) (string, error) {
+	in := strings.ToLower(strings.TrimSpace(s))
+	if in == "" {
+		return "", nil
+	}
+
+	if _, err := time.Parse("2006-01-02", in); err == nil {
+		return in, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch in {
+	case "today":
+		return today.Format("2006-01-02"), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	}
+
+	if wd, ok := weekdays[in]; ok {
+		days := (int(wd) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days).Format("2006-01-02"), nil
+	}
+
+	if len(in) >= 3 && in[0] == '+' {
+		n, err := strconv.Atoi(in[1 : len(in)-1])
+		if err == nil && n >= 0 {
+			switch in[len(in)-1] {
+			case 'd':
+				return today.AddDate(0, 0, n).Format("2006-01-02"), nil
+			case 'w':
+				return today.AddDate(0, 0, 7*n).Format("2006-01-02"), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("invalid due date %q (use YYYY-MM-DD, today, tomorrow, a weekday like friday, or an offset like +3d or +2w)", s)
+}
+
+// checkStartDate returns an error if a start date can't be sent with the
+// given due date: Asana only allows start dates on tasks with a due date,
+// and the start has to come first
+func checkStartDate(start, due string) error {
+	switch {
+	case start == "":
+		return nil
+	case due == "":
+		return fmt.Errorf("a start date needs a due date: Asana only schedules tasks with both (add --due)")
+	case start >= due:
+		return fmt.Errorf("start date %s must be before the due date %s", start, due)
+	}
+	return nil
+}
+
+// keepCurrentDue sets opts to re-send a task's current due date, which the
+// API wants along with a start date. A task due at a time gets its due_at
+// back, so it doesn't turn into an all-day task.
+func keepCurrentDue(opts *api.UpdateTaskOptions, task *api.Task) {
+	if task.DueAt != "" {
+		opts.DueAt = &task.DueAt
+	} else {
+		opts.DueOn = &task.DueOn
+	}
+}
+
+// dueRange checks min and max, each an optional YYYY-MM-DD date with min not
+// after max, and turns them into due_on.after and due_on.before values.
+// Those are exclusive, so they are moved out by a day to include min and max.
+func dueRange(min, max string) (after, before string, err error) {
+	shift := func(flag, d string, days int) (string, error) {
+		if d == "" {
+			return "", nil
+		}
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return "", fmt.Errorf("%s: invalid date %q (use YYYY-MM-DD)", flag, d)
+		}
+		return t.AddDate(0, 0, days).Format("2006-01-02"), nil
+	}
+	if after, err = shift("--min-due", min, -1); err != nil {
+		return "", "", err
+	}
+	if before, err = shift("--max-due", max, 1); err != nil {
+		return "", "", err
+	}
+	if min != "" && max != "" && min > max {
+		return "", "", fmt.Errorf("--min-due %s is after --max-due %s", min, max)
+	}
+	return after, before, nil
+}
+
+// Where and how API timestamps are displayed; see SetTimeFormat
+var (
+	displayLocation = time.Local
+	displayLayout   = "2006-01-02 15:04 MST"
+)
+
+// SetTimeFormat sets the time zone (an IANA name such as "Europe/Amsterdam")
+// and Go layout used to display API timestamps. Empty values keep the
+// defaults of local time and "2006-01-02 15:04 MST".
+func SetTimeFormat(tz, layout string) error {
+	if tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("invalid time zone %q: %w", tz, err)
+		}
+		displayLocation = loc
+	}
+	if layout != "" {
+		displayLayout = layout
+	}
+	return nil
+}
+
+// formatTime formats an API timestamp in the display time zone and layout.
+// Values that aren't timestamps are returned unchanged.
+func formatTime(timestamp string) string {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return t.In(displayLocation).Format(displayLayout)
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+type ExportCmd struct {
+	ProjectGID          string `arg:"" optional:"" help:"Project GID to export"`
+	Output              string `short:"o" help:"Output file path (defaults to stdout)" type:"path"`
+	NDJSON              bool   `help:"Write one JSON object per line instead of a JSON array"`
+	Comments            bool   `help:"Include comments and activity for each task"`
+	DownloadAttachments string `help:"Also download attachment files into this directory" type:"path" placeholder:"DIR"`
+}
+
+// exportedTask is a task together with its related records
+type exportedTask struct {
+	api.Task
+	Comments    []api.Story      `json:"comments,omitempty"`
+	Attachments []api.Attachment `json:"attachments"`
+}
+
+// MarshalJSON writes the task's fields followed by the related records.
+// Without it, api.Task's MarshalJSON would be promoted and encode the task
+// alone, dropping comments and attachments. Unknown task fields of the same
+// name are left out so that each key appears once.
+func (r exportedTask) MarshalJSON() ([]byte, error) {
+	t := r.Task
+	t.Extra = nil
+	for name, value := range r.Task.Extra {
+		if name == "comments" || name == "attachments" {
+			continue
+		}
+		if t.Extra == nil {
+			t.Extra = map[string]json.RawMessage{}
+		}
+		t.Extra[name] = value
+	}
+	task, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	more, err := json.Marshal(struct {
+		Comments    []api.Story      `json:"comments,omitempty"`
+		Attachments []api.Attachment `json:"attachments"`
+	}{r.Comments, r.Attachments})
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(task, []byte("{}")) {
+		return more, nil
+	}
+	// Splice the two objects: {"gid":"1"} + {"attachments":[]}
+	return append(append(task[:len(task)-1], ','), more[1:]...), nil
+}
+
+func (c *ExportCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickProject(client, g, &c.ProjectGID); err != nil {
+		return err
+	}
+
+	out := dataOut
+	var file *atomicFile
+	if c.Output != "" {
+		var err error
+		if file, err = createAtomic(c.Output); err != nil {
+			return err
+		}
+		defer file.abort()
+		out = file
+	}
+
+	if c.DownloadAttachments != "" {
+		if err := os.MkdirAll(c.DownloadAttachments, 0755); err != nil {
+			return fmt.Errorf("creating attachment directory: %w", err)
+		}
+	}
+
+	if !c.NDJSON {
+		fmt.Fprint(out, "[")
+	}
+
+	count := 0
+	err := client.EachProjectTask(c.ProjectGID, func(task api.Task) error {
+		record := exportedTask{Task: task}
+
+		if c.Comments {
+			stories, err := client.GetTaskStories(task.GID, false)
+			if err != nil {
+				return err
+			}
+			record.Comments = stories
+		}
+
+		attachments, err := client.ListAttachments(task.GID)
+		if err != nil {
+			return err
+		}
+		record.Attachments = attachments
+
+		if c.DownloadAttachments != "" {
+			for _, a := range attachments {
+				if err := c.download(client, a); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := c.write(out, record, count); err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !c.NDJSON {
+		if count > 0 {
+			fmt.Fprint(out, "\n")
+		}
+		fmt.Fprintln(out, "]")
+	}
+
+	if file != nil {
+		if err := file.commit(); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Exported %d tasks to %s\n", count, c.Output)
+	}
+
+	return nil
+}
+
+// write encodes a single record, as an array element or as one NDJSON line
+func (c *ExportCmd) write(out io.Writer, record exportedTask, index int) error {
+	if c.NDJSON {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("encoding JSON: %w", err)
+		}
+		_, err = fmt.Fprintf(out, "%s\n", data)
+		return err
+	}
+
+	data, err := json.MarshalIndent(record, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+
+	sep := ",\n  "
+	if index == 0 {
+		sep = "\n  "
+	}
+	_, err = fmt.Fprintf(out, "%s%s", sep, data)
+	return err
+}
+
+// download fetches an attachment file into the download directory.
+// External attachments (links) have no file and are skipped.
+func (c *ExportCmd) download(client *api.Client, a api.Attachment) error {
+	attachment, err := client.GetAttachment(a.GID)
+	if err != nil {
+		return err
+	}
+
+	if attachment.DownloadURL == "" {
+		fmt.Fprintf(os.Stderr, "Skipping %s: no downloadable file\n", attachment.Name)
+		return nil
+	}
+
+	destPath := filepath.Join(c.DownloadAttachments, attachment.GID+"_"+filepath.Base(attachment.Name))
+	return client.DownloadAttachment(attachment, destPath, api.DownloadOptions{})
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+func TestExportedTaskMarshalJSON(t *testing.T) {
+	record := exportedTask{
+		Task:        api.Task{GID: "1", Name: "Write report"},
+		Comments:    []api.Story{{GID: "2", Text: "Looks good"}},
+		Attachments: []api.Attachment{{GID: "3", Name: "report.pdf"}},
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	for _, key := range []string{"gid", "name", "comments", "attachments"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("%s missing from %s", key, data)
+		}
+	}
+}
+
+func TestExportedTaskMarshalJSONNoAttachments(t *testing.T) {
+	data, err := json.Marshal(exportedTask{Task: api.Task{GID: "1"}, Attachments: []api.Attachment{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"gid":"1","name":"","completed":false,"attachments":[]}`; string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestExportedTaskMarshalJSONExtraFields(t *testing.T) {
+	record := exportedTask{
+		Task: api.Task{GID: "1", Extra: map[string]json.RawMessage{
+			"comments":    json.RawMessage(`"from the API"`),
+			"attachments": json.RawMessage(`"from the API"`),
+			"html_notes":  json.RawMessage(`"<body></body>"`),
+		}},
+		Comments:    []api.Story{{GID: "2"}},
+		Attachments: []api.Attachment{},
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Count the top-level keys, as decoding into a map would hide duplicates
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		t.Fatal(err)
+	}
+	count := map[string]int{}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			t.Fatalf("invalid JSON %s: %v", data, err)
+		}
+		count[key.(string)]++
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			t.Fatalf("invalid JSON %s: %v", data, err)
+		}
+	}
+	for _, key := range []string{"gid", "comments", "attachments", "html_notes"} {
+		if count[key] != 1 {
+			t.Errorf("%s appears %d times in %s", key, count[key], data)
+		}
+	}
+	if record.Task.Extra["comments"] == nil {
+		t.Error("MarshalJSON changed the task's Extra map")
+	}
+}
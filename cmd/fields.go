@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// OptFieldsFlags lets read commands change the fields requested from the
+// API, for data the CLI has no column or struct field for. Extra fields show
+// up in JSON output.
+type OptFieldsFlags struct {
+	OptFields    string `help:"Comma-separated API fields to request instead of the defaults, e.g. name,num_subtasks" xor:"opt-fields"`
+	OptFieldsAdd string `help:"Comma-separated API fields to request in addition to the defaults" xor:"opt-fields"`
+}
+
+// withOptFields returns a client that requests the fields given by the flags
+func (f OptFieldsFlags) withOptFields(client *api.Client) *api.Client {
+	return client.WithOptFields(f.OptFields, f.OptFieldsAdd)
+}
+
+// column describes a column that can be shown in a table of T
+type column[T any] struct {
+	header    string
+	optFields []string // API fields needed to render the column
+	value     func(item T) string
+}
+
+// columnSet holds the columns that can be selected with --fields for one
+// kind of resource
+type columnSet[T any] struct {
+	names    []string // valid --fields names in display order
+	defaults string   // columns shown when --fields is not given
+	columns  map[string]column[T]
+}
+
+var taskColumns = columnSet[api.Task]{
+	names:    []string{"gid", "name", "due", "age", "assignee", "project", "projects", "tags", "completed", "completed_on", "created", "modified", "permalink"},
+	defaults: "gid,name,due,assignee,project",
+	columns: map[string]column[api.Task]{
+		"gid": {"GID", []string{"gid"}, func(t api.Task) string { return t.GID }},
+		"name": {"NAME", []string{"name"}, func(t api.Task) string {
+			if t.Parent != nil {
+				return "↳ " + truncate(t.Name, 48)
+			}
+			return truncate(t.Name, 50)
+		}},
+		"due": {"DUE", []string{"due_on"}, func(t api.Task) string {
+			return orDash(t.DueOn)
+		}},
+		"age": {"AGE", []string{"due_on"}, func(t api.Task) string {
+			return dueAge(t.DueOn, time.Now())
+		}},
+		"assignee": {"ASSIGNEE", []string{"assignee", "assignee.name"}, func(t api.Task) string {
+			if t.Assignee == nil {
+				return "-"
+			}
+			return t.Assignee.Name
+		}},
+		"project": {"PROJECT", []string{"projects", "projects.name"}, func(t api.Task) string {
+			switch len(t.Projects) {
+			case 0:
+				return "-"
+			case 1:
+				return t.Projects[0].Name
+			default:
+				// Don't let a multi-project task look like it's in one project
+				return fmt.Sprintf("%s (+%d)", truncate(t.Projects[0].Name, 30), len(t.Projects)-1)
+			}
+		}},
+		"projects": {"PROJECTS", []string{"projects", "projects.name"}, func(t api.Task) string {
+			names := make([]string, len(t.Projects))
+			for i, p := range t.Projects {
+				names[i] = p.Name
+			}
+			return orDash(truncate(strings.Join(names, ", "), 50))
+		}},
+		"tags": {"TAGS", []string{"tags", "tags.name"}, func(t api.Task) string {
+			names := make([]string, len(t.Tags))
+			for i, tag := range t.Tags {
+				names[i] = tag.Name
+			}
+			return orDash(truncate(strings.Join(names, ", "), 40))
+		}},
+		"completed": {"COMPLETED", []string{"completed"}, func(t api.Task) string {
+			if t.Completed {
+				return "Yes"
+			}
+			return "No"
+		}},
+		"completed_on": {"COMPLETED ON", []string{"completed_at"}, func(t api.Task) string {
+			return orDash(datePart(t.CompletedAt))
+		}},
+		"created": {"CREATED", []string{"created_at"}, func(t api.Task) string {
+			return orDash(datePart(t.CreatedAt))
+		}},
+		"modified": {"MODIFIED", []string{"modified_at"}, func(t api.Task) string {
+			return orDash(datePart(t.ModifiedAt))
+		}},
+		"permalink": {"URL", []string{"permalink_url"}, func(t api.Task) string {
+			return orDash(t.Permalink)
+		}},
+	},
+}
+
+var projectColumns = columnSet[api.Project]{
+	names:    []string{"gid", "name", "archived", "color", "created", "permalink"},
+	defaults: "gid,name,archived,created",
+	columns: map[string]column[api.Project]{
+		"gid": {"GID", []string{"gid"}, func(p api.Project) string { return p.GID }},
+		"name": {"NAME", []string{"name"}, func(p api.Project) string {
+			return truncate(p.Name, 40)
+		}},
+		"archived": {"ARCHIVED", []string{"archived"}, func(p api.Project) string {
+			if p.Archived {
+				return "Yes"
+			}
+			return "No"
+		}},
+		"color": {"COLOR", []string{"color"}, func(p api.Project) string {
+			return orDash(p.Color)
+		}},
+		"created": {"CREATED", []string{"created_at"}, func(p api.Project) string {
+			return orDash(datePart(p.CreatedAt))
+		}},
+		"permalink": {"URL", []string{"permalink_url"}, func(p api.Project) string {
+			return orDash(p.Permalink)
+		}},
+	},
+}
+
+var userColumns = columnSet[api.User]{
+	names:    []string{"gid", "name", "email"},
+	defaults: "gid,name,email",
+	columns: map[string]column[api.User]{
+		"gid":  {"GID", []string{"gid"}, func(u api.User) string { return u.GID }},
+		"name": {"NAME", []string{"name"}, func(u api.User) string { return u.Name }},
+		"email": {"EMAIL", []string{"email"}, func(u api.User) string {
+			return orDash(u.Email)
+		}},
+	},
+}
+
+// parse validates a comma-separated --fields value
+func (s columnSet[T]) parse(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = s.defaults
+	}
+
+	var fields []string
+	for _, f := range strings.Split(spec, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if _, ok := s.columns[f]; !ok {
+			return nil, fmt.Errorf("unknown field %q (valid fields: %s)", f, strings.Join(s.names, ", "))
+		}
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no fields given (valid fields: %s)", strings.Join(s.names, ", "))
+	}
+	return fields, nil
+}
+
+// optFields returns the opt_fields value needed to render the given columns
+func (s columnSet[T]) optFields(fields []string) string {
+	seen := map[string]bool{"gid": true}
+	optFields := []string{"gid"}
+	for _, f := range fields {
+		for _, of := range s.columns[f].optFields {
+			if !seen[of] {
+				seen[of] = true
+				optFields = append(optFields, of)
+			}
+		}
+	}
+	return strings.Join(optFields, ",")
+}
+
+// table builds a table of items with the given columns
+func (s columnSet[T]) table(items []T, fields []string) *table {
+	headers := make([]string, len(fields))
+	for i, f := range fields {
+		headers[i] = s.columns[f].header
+	}
+
+	t := newTable(headers...)
+	for _, item := range items {
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			values[i] = s.columns[f].value(item)
+		}
+		t.row(values...)
+	}
+
+	return t
+}
+
+// hasField reports whether name is one of the selected columns
+func hasField(fields []string, name string) bool {
+	for _, f := range fields {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// dueAge describes a due date relative to now: "3d" for three days
+// overdue, "today", or "in 2d" for upcoming tasks
+func dueAge(dueOn string, now time.Time) string {
+	due, err := time.ParseInLocation("2006-01-02", dueOn, now.Location())
+	if err != nil {
+		return "-"
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	days := int(math.Round(today.Sub(due).Hours() / 24))
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd", days)
+	case days == 0:
+		return "today"
+	default:
+		return fmt.Sprintf("in %dd", -days)
+	}
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// datePart returns the YYYY-MM-DD date of an API timestamp in the display
+// time zone
+func datePart(timestamp string) string {
+	if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return t.In(displayLocation).Format("2006-01-02")
+	}
+	if len(timestamp) > 10 {
+		return timestamp[:10]
+	}
+	return timestamp
+}
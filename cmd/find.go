@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// findTypes are the resource types searched by find, in display order
+var findTypes = []string{"task", "project", "user", "tag"}
+
+// FindCmd looks up tasks, projects, users and tags by name
+type FindCmd struct {
+	Query string `arg:"" help:"Text to look for in names"`
+	Type  string `short:"t" enum:",task,project,user,tag" default:"" help:"Only search this type: task, project, user or tag"`
+	Limit int    `short:"l" default:"10" help:"Maximum results per type"`
+	JSON  bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *FindCmd) Run(client *api.Client, g *Globals) error {
+	types := findTypes
+	if c.Type != "" {
+		types = []string{c.Type}
+	}
+
+	// Query all types concurrently
+	results := make([][]api.TypeaheadResult, len(types))
+	fetches := make([]func(ctx context.Context) error, len(types))
+	for i, typ := range types {
+		i, typ := i, typ
+		fetches[i] = func(ctx context.Context) (err error) {
+			results[i], err = client.WithContext(ctx).Typeahead(c.Query, typ, c.Limit)
+			return err
+		}
+	}
+	if err := runParallel(fetches...); err != nil {
+		return err
+	}
+
+	if c.JSON {
+		grouped := make(map[string][]api.TypeaheadResult, len(types))
+		for i, typ := range types {
+			grouped[typ+"s"] = results[i]
+			if grouped[typ+"s"] == nil {
+				grouped[typ+"s"] = []api.TypeaheadResult{}
+			}
+		}
+		return printJSON(grouped)
+	}
+
+	t := newTable("TYPE", "GID", "NAME")
+	found := 0
+	for i, typ := range types {
+		for _, r := range results[i] {
+			t.row(strings.ToUpper(typ), r.GID, truncate(r.Name, 60))
+			found++
+		}
+	}
+
+	if found == 0 {
+		fmt.Printf("Nothing found matching %q.\n", c.Query)
+		return nil
+	}
+	return t.print(g)
+}
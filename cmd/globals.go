@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/mauricejumelet/asana-cli/internal/config"
+)
+
+// Globals holds the flags shared by all commands. It is embedded in the root
+// CLI struct and bound so that commands can take it as a Run parameter.
+type Globals struct {
+	Config          string        `short:"c" help:"Path to config file (.env, or .toml/.yaml/.yml by extension)" type:"path"`
+	Profile         string        `help:"Profile to use from a TOML or YAML config file (default: ASANA_PROFILE)"`
+	TokenCommand    string        `help:"Command that prints the API token, e.g. 'pass asana/token' (default: ASANA_TOKEN_COMMAND)"`
+	Workspace       string        `short:"w" help:"Workspace GID or name to use instead of ASANA_WORKSPACE"`
+	BaseURL         string        `help:"API base URL, e.g. for a proxy or mock server (default: ASANA_BASE_URL or the Asana API)"`
+	Verbose         bool          `short:"V" help:"Log HTTP requests and responses to stderr"`
+	UseCache        bool          `name:"cache" xor:"cache" help:"Cache GET responses for --cache-ttl, ASANA_CACHE_TTL, or 60s if neither is set"`
+	NoCache         bool          `xor:"cache" help:"Bypass the response cache"`
+	CacheTTL        time.Duration `help:"Cache GET responses for this long, e.g. 5m (default: ASANA_CACHE_TTL, or no caching without --cache)"`
+	MaxConcurrent   int           `name:"max-concurrent-requests" default:"5" help:"Most API requests to have in flight at once, across all parallel work (0 for no limit)"`
+	RetryOnConflict bool          `help:"Retry updates that fail because someone else changed the item at the same time (409/412), up to 3 times"`
+	Out             string        `help:"Write JSON output to this file instead of stdout (written atomically)" type:"path"`
+	YAML            bool          `name:"yaml" help:"Print the output of --json as YAML instead, for commands that have --json"`
+	NoColor         bool          `help:"Disable colored output (also disabled by the NO_COLOR environment variable)"`
+	NoPager         bool          `help:"Never pipe long tables through $PAGER"`
+	NoDefaults      bool          `help:"Ignore ASANA_DEFAULT_PROJECT and ASANA_DEFAULT_ASSIGNEE"`
+	Interactive     bool          `short:"i" help:"Pick missing task, project or user arguments from a list"`
+	Yes             bool          `short:"y" help:"Answer yes to every confirmation prompt, for scripts and CI"`
+	Quiet           bool          `short:"q" help:"Print only the GID of created or changed items (create, complete, reopen, comment, upload)"`
+	TZ              string        `name:"tz" help:"Time zone for displayed times, e.g. Europe/Amsterdam (default: ASANA_TZ or the local time zone)"`
+	DateFormat      string        `default:"2006-01-02 15:04 MST" help:"Go layout for displayed times"`
+	Picker          string        `enum:"auto,builtin,fzf" default:"auto" help:"Picker for --interactive: auto (fzf if installed), builtin or fzf"`
+}
+
+// defaults returns the configured default project and assignee, or empty
+// strings with --no-defaults
+func (g *Globals) defaults(cfg *config.Config) (project, assignee string) {
+	if g.NoDefaults {
+		return "", ""
+	}
+	return cfg.DefaultProject, cfg.DefaultAssignee
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+type ImportCmd struct {
+	File    string `arg:"" help:"CSV or JSON file with one task per row" type:"existingfile"`
+	Project string `short:"p" help:"Default project GID or name for rows without a project"`
+	Format  string `help:"Input format: csv or json (defaults to the file extension)" enum:",csv,json" default:""`
+	DryRun  bool   `help:"Show what would be created without creating anything"`
+}
+
+// importRow is a single task to create. CSV files use the same column names.
+type importRow struct {
+	Name     string   `json:"name"`
+	Notes    string   `json:"notes"`
+	Assignee string   `json:"assignee"`
+	DueOn    string   `json:"due_on"`
+	Project  string   `json:"project"`
+	Tags     []string `json:"tags"`
+}
+
+func (c *ImportCmd) Run(client *api.Client) error {
+	rows, err := c.readRows()
+	if err != nil {
+		return err
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("No rows to import.")
+		return nil
+	}
+
+	// Resolve each distinct assignee/project/tag once
+	users := map[string]string{}
+	projects := map[string]string{}
+	tags := map[string]string{}
+
+	created, failed := 0, 0
+	for i, row := range rows {
+		line := i + 1
+
+		gid, err := c.importRow(client, row, users, projects, tags)
+		switch {
+		case err != nil:
+			failed++
+			fmt.Printf("Row %d: failed (%s): %v\n", line, row.Name, err)
+		case c.DryRun:
+			created++
+			fmt.Printf("Row %d: would create %q\n", line, row.Name)
+		default:
+			created++
+			fmt.Printf("Row %d: created %s (%s)\n", line, gid, row.Name)
+		}
+	}
+
+	verb := "Created"
+	if c.DryRun {
+		verb = "Would create"
+	}
+	fmt.Printf("\n%s %d tasks, %d failed.\n", verb, created, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", failed, len(rows))
+	}
+	return nil
+}
+
+func (c *ImportCmd) importRow(client *api.Client, row importRow, users, projects, tags map[string]string) (string, error) {
+	if strings.TrimSpace(row.Name) == "" {
+		return "", fmt.Errorf("missing name")
+	}
+
+	opts := api.CreateTaskOptions{
+		Name:  row.Name,
+		Notes: row.Notes,
+		DueOn: row.DueOn,
+	}
+
+	if row.Assignee != "" {
+		gid, ok := users[row.Assignee]
+		if !ok {
+			var err error
+			gid, err = resolveUser(client, row.Assignee)
+			if err != nil {
+				return "", err
+			}
+			users[row.Assignee] = gid
+		}
+		opts.Assignee = gid
+	}
+
+	project := row.Project
+	if project == "" {
+		project = c.Project
+	}
+	if project != "" {
+		gid, ok := projects[project]
+		if !ok {
+			var err error
+			gid, err = resolveProject(client, project)
+			if err != nil {
+				return "", err
+			}
+			projects[project] = gid
+		}
+		opts.Projects = []string{gid}
+	}
+
+	for _, tag := range row.Tags {
+		gid, ok := tags[tag]
+		if !ok {
+			gid = tag
+			if !isGID(tag) {
+				var err error
+				if gid, err = resolveTag(client, tag); err != nil {
+					return "", err
+				}
+			}
+			tags[tag] = gid
+		}
+		opts.Tags = append(opts.Tags, gid)
+	}
+
+	if c.DryRun {
+		return "", nil
+	}
+
+	task, err := client.CreateTask(opts)
+	if err != nil {
+		return "", err
+	}
+	return task.GID, nil
+}
+
+func (c *ImportCmd) readRows() ([]importRow, error) {
+	format := c.Format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(c.File)), ".")
+	}
+
+	f, err := os.Open(c.File)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	switch format {
+	case "json":
+		var rows []importRow
+		if err := json.NewDecoder(f).Decode(&rows); err != nil {
+			return nil, fmt.Errorf("parsing JSON: %w", err)
+		}
+		return rows, nil
+	case "csv":
+		return readCSVRows(f)
+	default:
+		return nil, fmt.Errorf("cannot detect format of %s, use --format csv or --format json", c.File)
+	}
+}
+
+// readCSVRows reads tasks from CSV with a header row. Columns are matched by
+// name (name, notes, assignee, due_on, project, tags); tags are comma-separated
+// names or GIDs.
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, fmt.Errorf("CSV must have a 'name' column")
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := importRow{
+			Name:     field("name"),
+			Notes:    field("notes"),
+			Assignee: field("assignee"),
+			DueOn:    field("due_on"),
+			Project:  field("project"),
+		}
+		for _, tag := range strings.Split(field("tags"), ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				row.Tags = append(row.Tags, tag)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
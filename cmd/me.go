@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+type MeCmd struct {
+	Tasks MeTasksCmd `cmd:"" help:"List your My Tasks in the order shown in Asana"`
+}
+
+type MeTasksCmd struct {
+	Limit int  `short:"l" default:"100" help:"Maximum number of tasks to return (0 for no limit)"`
+	JSON  bool `short:"j" help:"Output as JSON"`
+	OptFieldsFlags
+}
+
+func (c *MeTasksCmd) Run(client *api.Client, g *Globals) error {
+	tasks, err := c.withOptFields(client).GetMyTaskList(c.Limit)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(tasks)
+	}
+	return printUserTaskList(g, tasks, c.Limit)
+}
+
+// printUserTaskList shows the tasks of a My Tasks list with their My Tasks
+// section, as me tasks and users tasks do
+func printUserTaskList(g *Globals, tasks []api.Task, limit int) error {
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found.")
+		return nil
+	}
+
+	t := newTable("GID", "NAME", "DUE", "SECTION", "PROJECT")
+
+	for _, task := range tasks {
+		due := "-"
+		if task.DueOn != "" {
+			due = task.DueOn
+		}
+
+		section := "-"
+		if task.AssigneeSection != nil && task.AssigneeSection.Name != "" {
+			section = task.AssigneeSection.Name
+		}
+
+		project := "-"
+		if len(task.Projects) > 0 {
+			project = task.Projects[0].Name
+		}
+
+		name := truncate(task.Name, 50)
+		t.row(task.GID, name, due, section, project)
+	}
+
+	if limit > 0 && len(tasks) >= limit {
+		t.footerf("(Showing %d tasks, use -l to increase limit)", limit)
+	}
+	return t.print(g)
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/alecthomas/kong"
+)
+
+// dataOut receives machine-readable output (JSON). It is stdout unless
+// --out redirects it to a file.
+var dataOut io.Writer = os.Stdout
+
+// outputYAML makes printJSON write YAML; see SetYAMLOutput
+var outputYAML bool
+
+// SetYAMLOutput handles --yaml: it turns on the selected command's --json
+// flag and makes that output YAML. Commands without --json are rejected.
+// Kong has checked the xor groups before --json is turned on here, so
+// flags that can't be combined with --json (--raw, --count-only, ...) are
+// checked again.
+func SetYAMLOutput(ctx *kong.Context, enabled bool) error {
+	if !enabled {
+		return nil
+	}
+
+	for _, f := range ctx.Flags() {
+		if f.Name != "json" {
+			continue
+		}
+		if flagProvided(ctx, "json") {
+			return fmt.Errorf("--json and --yaml can't be used together")
+		}
+		if other := xorFlagProvided(ctx, f); other != "" {
+			return fmt.Errorf("--%s and --yaml can't be used together", other)
+		}
+		f.Target.SetBool(true)
+		outputYAML = true
+		return nil
+	}
+	return fmt.Errorf("--yaml only works with commands that have --json output")
+}
+
+// xorFlagProvided returns the name of a flag given on the command line that
+// shares an xor group with flag, or "" if there is none
+func xorFlagProvided(ctx *kong.Context, flag *kong.Flag) string {
+	for _, f := range ctx.Flags() {
+		if f == flag || !flagProvided(ctx, f.Name) {
+			continue
+		}
+		for _, a := range f.Xor {
+			for _, b := range flag.Xor {
+				if a == b {
+					return f.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// atomicFile is written under a temporary name and only moved into place
+// by commit, so a failed run never leaves a partial file behind
+type atomicFile struct {
+	*os.File
+	path    string
+	written int64
+}
+
+func createAtomic(path string) (*atomicFile, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating output file: %w", err)
+	}
+	return &atomicFile{File: f, path: path}, nil
+}
+
+func (f *atomicFile) Write(b []byte) (int, error) {
+	n, err := f.File.Write(b)
+	f.written += int64(n)
+	return n, err
+}
+
+// commit replaces the destination with the written data
+func (f *atomicFile) commit() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.File.Name())
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	if err := os.Rename(f.File.Name(), f.path); err != nil {
+		os.Remove(f.File.Name())
+		return fmt.Errorf("writing output file: %w", err)
+	}
+	return nil
+}
+
+// abort discards the written data
+func (f *atomicFile) abort() {
+	f.File.Close()
+	os.Remove(f.File.Name())
+}
+
+// RedirectOutput sends JSON output to path instead of stdout. The returned
+// function must be called with the command's result: the file is only
+// created if the command succeeded and actually produced output.
+func RedirectOutput(path string) (func(error) error, error) {
+	if path == "" {
+		return func(err error) error { return err }, nil
+	}
+
+	f, err := createAtomic(path)
+	if err != nil {
+		return nil, err
+	}
+	dataOut = f
+
+	return func(err error) error {
+		dataOut = os.Stdout
+		if err != nil {
+			f.abort()
+			return err
+		}
+		if f.written == 0 {
+			f.abort()
+			fmt.Fprintf(os.Stderr, "Nothing was written to %s (--out only captures JSON output, try -j)\n", path)
+			return nil
+		}
+		if err := f.commit(); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Wrote %s\n", path)
+		return nil
+	}, nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+
+	"golang.org/x/term"
+)
+
+// defaultPager is used when $PAGER is not set
+const defaultPager = "less -FRX"
+
+// pageOutput writes out to stdout. When stdout is a terminal and out is
+// taller than it, the output is piped through $PAGER instead.
+func pageOutput(g *Globals, out []byte) error {
+	if g.NoPager || !isTerminal(os.Stdout) || bytes.Count(out, []byte("\n")) < terminalHeight() {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		if runtime.GOOS == "windows" {
+			_, err := os.Stdout.Write(out)
+			return err
+		}
+		pager = defaultPager
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", pager)
+	} else {
+		cmd = exec.Command("sh", "-c", pager)
+	}
+	// exec ignores EPIPE while feeding stdin, so quitting the pager early is harmless
+	cmd.Stdin = bytes.NewReader(out)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		// Pager not available, fall back to plain output
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	_ = cmd.Wait()
+	return nil
+}
+
+// isTerminal reports whether f is connected to a terminal
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// terminalSize returns the columns and rows of the terminal on stdout,
+// or zeros if it cannot be determined
+func terminalSize() (cols, rows int) {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0, 0
+	}
+	return cols, rows
+}
+
+// terminalHeight returns the number of rows of the terminal on stdout,
+// falling back to $LINES or 24
+func terminalHeight() int {
+	if _, rows := terminalSize(); rows > 0 {
+		return rows
+	}
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		return lines
+	}
+	return 24
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// pickerItem is one choice offered by the interactive picker
+type pickerItem struct {
+	GID   string
+	Label string
+}
+
+// maxPickerRows limits how many matches the built-in picker prints at once
+const maxPickerRows = 20
+
+// canPick reports whether interactive selection is enabled and possible
+func canPick(g *Globals) bool {
+	return g.Interactive && isTerminal(os.Stdin)
+}
+
+// pickTask leaves *gid alone if set, otherwise lets the user pick one of
+// their open tasks when running interactively.
+func pickTask(client *api.Client, g *Globals, gid *string) error {
+	if *gid != "" {
+		return nil
+	}
+	if !canPick(g) {
+		return fmt.Errorf("a task GID is required (or use -i to pick one interactively)")
+	}
+
+	tasks, err := client.ListTasks(api.TaskListOptions{Assignee: "me", Limit: 100, SortBy: "modified_at", SortDescending: true})
+	if err != nil {
+		return err
+	}
+
+	items := make([]pickerItem, 0, len(tasks))
+	for _, t := range tasks {
+		label := t.Name
+		if t.DueOn != "" {
+			label += "  (due " + t.DueOn + ")"
+		}
+		items = append(items, pickerItem{GID: t.GID, Label: label})
+	}
+	picked, err := pick(g, "task", items)
+	*gid = picked
+	return err
+}
+
+// pickProject leaves *gid alone if set, otherwise lets the user pick a
+// project when running interactively.
+func pickProject(client *api.Client, g *Globals, gid *string) error {
+	if *gid != "" {
+		return nil
+	}
+	if !canPick(g) {
+		return fmt.Errorf("a project GID is required (or use -i to pick one interactively)")
+	}
+
+	projects, _, err := client.ListProjects(api.ActiveProjects, 100, "", "")
+	if err != nil {
+		return err
+	}
+
+	items := make([]pickerItem, 0, len(projects))
+	for _, p := range projects {
+		items = append(items, pickerItem{GID: p.GID, Label: p.Name})
+	}
+	picked, err := pick(g, "project", items)
+	*gid = picked
+	return err
+}
+
+// pickUser leaves *ref alone if set, otherwise lets the user pick a
+// workspace member when running interactively.
+func pickUser(client *api.Client, g *Globals, ref *string) error {
+	if *ref != "" {
+		return nil
+	}
+	if !canPick(g) {
+		return fmt.Errorf("a user is required (or use -i to pick one interactively)")
+	}
+
+	users, err := client.ListUsers("")
+	if err != nil {
+		return err
+	}
+
+	items := make([]pickerItem, 0, len(users))
+	for _, u := range users {
+		label := u.Name
+		if u.Email != "" {
+			label += " <" + u.Email + ">"
+		}
+		items = append(items, pickerItem{GID: u.GID, Label: label})
+	}
+	picked, err := pick(g, "user", items)
+	*ref = picked
+	return err
+}
+
+// pick asks the user to choose one of items and returns its GID. fzf is
+// used when requested or, with the default "auto" picker, when installed.
+func pick(g *Globals, kind string, items []pickerItem) (string, error) {
+	if len(items) == 0 {
+		return "", fmt.Errorf("no %ss to choose from", kind)
+	}
+
+	switch g.Picker {
+	case "fzf":
+		return pickFzf(kind, items)
+	case "auto":
+		if _, err := exec.LookPath("fzf"); err == nil {
+			return pickFzf(kind, items)
+		}
+	}
+	return pickBuiltin(kind, items)
+}
+
+// pickFzf shells out to fzf, showing labels and returning the GID of the
+// selected line.
+func pickFzf(kind string, items []pickerItem) (string, error) {
+	var in bytes.Buffer
+	for _, item := range items {
+		fmt.Fprintf(&in, "%s\t%s\n", item.GID, item.Label)
+	}
+
+	cmd := exec.Command("fzf", "--delimiter=\t", "--with-nth=2..", "--prompt="+kind+"> ")
+	cmd.Stdin = &in
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no %s selected", kind)
+	}
+
+	gid, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\t")
+	return gid, nil
+}
+
+// pickBuiltin is a minimal line-based picker: typing text narrows the list
+// with a fuzzy match, typing a number selects that entry.
+func pickBuiltin(kind string, items []pickerItem) (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	matches := items
+
+	for {
+		for i, item := range matches {
+			if i == maxPickerRows {
+				fmt.Fprintf(os.Stderr, "  ... %d more, type to narrow down\n", len(matches)-maxPickerRows)
+				break
+			}
+			fmt.Fprintf(os.Stderr, "%3d) %s\n", i+1, item.Label)
+		}
+		fmt.Fprintf(os.Stderr, "Select a %s (number, or text to filter; empty to cancel): ", kind)
+
+		line, err := reader.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if err != nil || len(matches) != 1 {
+				return "", fmt.Errorf("no %s selected", kind)
+			}
+			return matches[0].GID, nil
+		}
+
+		if n, err := strconv.Atoi(line); err == nil {
+			if n >= 1 && n <= len(matches) && n <= maxPickerRows {
+				return matches[n-1].GID, nil
+			}
+			fmt.Fprintf(os.Stderr, "Invalid selection %d.\n", n)
+			continue
+		}
+
+		filtered := fuzzyFilter(items, line)
+		if len(filtered) == 0 {
+			fmt.Fprintf(os.Stderr, "No %ss match %q.\n", kind, line)
+			continue
+		}
+		matches = filtered
+	}
+}
+
+// fuzzyFilter returns the items whose label contains the characters of
+// query in order, ignoring case
+func fuzzyFilter(items []pickerItem, query string) []pickerItem {
+	query = strings.ToLower(query)
+	var out []pickerItem
+	for _, item := range items {
+		if fuzzyMatch(strings.ToLower(item.Label), query) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func fuzzyMatch(s, query string) bool {
+	for _, r := range query {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// PingCmd checks that the CLI can reach and authenticate with the API
+type PingCmd struct{}
+
+func (c *PingCmd) Run(client *api.Client) error {
+	fmt.Printf("Base URL: %s\n", client.BaseURL())
+
+	user, latency, err := client.Ping()
+	if err != nil {
+		return pingFailed(pingCategory(err), err)
+	}
+	fmt.Printf("Latency: %s\n", latency.Round(time.Millisecond))
+	fmt.Printf("Auth: OK (%s)\n", user.Name)
+
+	gid := client.Workspace()
+	if gid == "" {
+		return pingFailed("workspace", fmt.Errorf("no workspace set (use ASANA_WORKSPACE or --workspace)"))
+	}
+	workspace, err := client.GetWorkspace(gid)
+	if err != nil {
+		category := pingCategory(err)
+		if errors.Is(err, api.ErrNotFound) || errors.Is(err, api.ErrForbidden) {
+			category = "workspace"
+			err = fmt.Errorf("workspace %s not found or not accessible to %s", gid, user.Name)
+		}
+		return pingFailed(category, err)
+	}
+	fmt.Printf("Workspace: OK (%s, %s)\n", workspace.Name, workspace.GID)
+
+	return nil
+}
+
+// pingFailed prints a failed check and returns the error to exit with
+func pingFailed(category string, err error) error {
+	fmt.Printf("FAILED (%s): %v\n", category, api.ExplainAuthError(err))
+	return fmt.Errorf("ping failed: %s", category)
+}
+
+// pingCategory names the kind of problem behind a failed request
+func pingCategory(err error) string {
+	var dnsErr *net.DNSError
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var netErr net.Error
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return "dns"
+	case errors.As(err, &certErr), errors.As(err, &recordErr), errors.As(err, &authorityErr), errors.As(err, &hostnameErr):
+		return "tls"
+	case errors.Is(err, api.ErrUnauthorized), errors.Is(err, api.ErrForbidden):
+		return "auth"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return "network"
+	default:
+		return "api"
+	}
+}
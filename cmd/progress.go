@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// progressBarWidth is the number of characters in the bar itself
+const progressBarWidth = 30
+
+// progressBar draws a single-line progress indicator, redrawing at most
+// a few times per second
+type progressBar struct {
+	out   io.Writer
+	label string
+	last  time.Time
+}
+
+func newProgressBar(out io.Writer, label string) *progressBar {
+	return &progressBar{out: out, label: label}
+}
+
+// update redraws the bar; total is -1 when the size is unknown
+func (p *progressBar) update(written, total int64) {
+	if time.Since(p.last) < 100*time.Millisecond && written != total {
+		return
+	}
+	p.last = time.Now()
+
+	if total <= 0 {
+		fmt.Fprintf(p.out, "\r%s %s", p.label, formatSize(written))
+		return
+	}
+
+	filled := int(float64(progressBarWidth) * float64(written) / float64(total))
+	if filled > progressBarWidth {
+		filled = progressBarWidth
+	}
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	fmt.Fprintf(p.out, "\r%s [%s] %3d%% %s / %s", p.label, bar, written*100/total, formatSize(written), formatSize(total))
+}
+
+// done ends the progress line
+func (p *progressBar) done() {
+	fmt.Fprintln(p.out)
+}
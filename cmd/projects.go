@@ -2,28 +2,74 @@ package cmd
 
 import (
 	"fmt"
-	"os"
-	"text/tabwriter"
+	"strings"
+	"time"
 
 	"github.com/mauricejumelet/asana-cli/internal/api"
 )
 
 type ProjectsCmd struct {
-	List ProjectsListCmd `cmd:"" help:"List projects in the workspace"`
+	List      ProjectsListCmd      `cmd:"" help:"List projects in the workspace"`
+	Get       ProjectsGetCmd       `cmd:"" help:"Get a project by ID or name"`
+	Tasks     ProjectsTasksCmd     `cmd:"" help:"List a project's tasks in project order"`
+	Status    ProjectsStatusCmd    `cmd:"" help:"Post and list project status updates"`
+	Members   ProjectsMembersCmd   `cmd:"" help:"List and change who is on a project"`
+	Duplicate ProjectsDuplicateCmd `cmd:"" help:"Copy a project"`
+	Create    ProjectsCreateCmd    `cmd:"" help:"Create a project from a project template"`
 }
 
 type ProjectsListCmd struct {
-	Archived bool `short:"a" help:"Include archived projects"`
-	Limit    int  `short:"l" default:"50" help:"Maximum number of projects to return"`
-	JSON     bool `short:"j" help:"Output as JSON"`
+	Archived     bool   `short:"a" help:"Include archived projects" xor:"archived"`
+	ArchivedOnly bool   `help:"List only archived projects" xor:"archived"`
+	Team         string `short:"t" help:"Only list the projects of this team (GID or name)"`
+	Limit        int    `short:"l" default:"50" help:"Maximum number of projects to return (0 for no limit)"`
+	Fields       string `help:"Comma-separated columns to show (gid,name,archived,color,created,permalink)"`
+	JSON         bool   `short:"j" help:"Output as JSON"`
+	JSONMeta     bool   `help:"Output as JSON wrapped with count, next_offset and workspace"`
+	After        string `placeholder:"OFFSET" help:"Continue from the next_offset of a previous run (same flags, --limit 1-100)"`
+	OptFieldsFlags
 }
 
-func (c *ProjectsListCmd) Run(client *api.Client) error {
-	projects, err := client.ListProjects(c.Archived, c.Limit)
+func (c *ProjectsListCmd) Run(client *api.Client, g *Globals) error {
+	fields, err := projectColumns.parse(c.Fields)
 	if err != nil {
 		return err
 	}
 
+	optFields := ""
+	if c.Fields != "" {
+		optFields = projectColumns.optFields(fields)
+	}
+
+	archived := api.ActiveProjects
+	switch {
+	case c.Archived:
+		archived = api.AllProjects
+	case c.ArchivedOnly:
+		archived = api.ArchivedProjects
+	}
+
+	var projects []api.Project
+	var next *api.Page
+	if c.Team != "" {
+		team, err := resolveTeam(client, c.Team)
+		if err != nil {
+			return err
+		}
+		projects, next, err = c.withOptFields(client).ListTeamProjects(team, archived, c.Limit, optFields, c.After)
+		if err != nil {
+			return notFound(explainAfter(err, c.After), "team", team)
+		}
+	} else {
+		projects, next, err = c.withOptFields(client).ListProjects(archived, c.Limit, optFields, c.After)
+		if err != nil {
+			return explainAfter(err, c.After)
+		}
+	}
+
+	if c.JSONMeta {
+		return printJSONEnvelope(projects, len(projects), next, client.Workspace())
+	}
 	if c.JSON {
 		return printJSON(projects)
 	}
@@ -33,25 +79,384 @@ func (c *ProjectsListCmd) Run(client *api.Client) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "GID\tNAME\tARCHIVED\tCREATED")
-	fmt.Fprintln(w, "---\t----\t--------\t-------")
+	t := projectColumns.table(projects, fields)
+	if next != nil && next.Offset != "" {
+		t.footerf("(More projects: continue with --after %s)", next.Offset)
+	}
+	return t.print(g)
+}
 
-	for _, project := range projects {
-		archived := "No"
-		if project.Archived {
-			archived = "Yes"
+type ProjectsGetCmd struct {
+	ProjectGID string `arg:"" optional:"" help:"Project GID or name"`
+	Web        bool   `help:"Open the project in the browser instead of printing it (no -w, which is --workspace)"`
+	JSON       bool   `short:"j" help:"Output as JSON"`
+	OptFieldsFlags
+}
+
+func (c *ProjectsGetCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickProject(client, g, &c.ProjectGID); err != nil {
+		return err
+	}
+	gid, err := resolveProject(client, c.ProjectGID)
+	if err != nil {
+		return err
+	}
+
+	project, err := c.withOptFields(client).GetProject(gid)
+	if err != nil {
+		return notFound(err, "project", gid)
+	}
+
+	if c.Web {
+		url := project.Permalink
+		if url == "" {
+			url = fmt.Sprintf("https://app.asana.com/0/%s/list", project.GID)
 		}
+		return openBrowser(url)
+	}
+
+	if c.JSON {
+		return printJSON(project)
+	}
+
+	fmt.Printf("Project: %s\n", project.Name)
+	fmt.Printf("GID: %s\n", project.GID)
+	if project.Owner != nil {
+		fmt.Printf("Owner: %s\n", project.Owner.Name)
+	}
+	if project.Archived {
+		fmt.Println("Archived: Yes")
+	}
+	if project.CreatedAt != "" {
+		fmt.Printf("Created: %s\n", datePart(project.CreatedAt))
+	}
+	if project.Permalink != "" {
+		fmt.Printf("URL: %s\n", project.Permalink)
+	}
+	if project.Notes != "" {
+		fmt.Printf("\nNotes:\n%s\n", project.Notes)
+	}
+
+	return nil
+}
+
+// ProjectsTasksCmd lists a project's tasks in the order they appear in the
+// project, which the search API used by tasks list can't provide
+type ProjectsTasksCmd struct {
+	ProjectGID       string `arg:"" optional:"" help:"Project GID or name"`
+	IncludeCompleted bool   `help:"Include completed tasks"`
+	Limit            int    `short:"l" default:"100" help:"Maximum number of tasks to return (0 for no limit)"`
+	Fields           string `help:"Comma-separated columns to show (gid,name,due,age,assignee,project,projects,tags,completed,completed_on,created,modified,permalink)"`
+	JSON             bool   `short:"j" help:"Output as JSON"`
+	OptFieldsFlags
+}
+
+func (c *ProjectsTasksCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickProject(client, g, &c.ProjectGID); err != nil {
+		return err
+	}
+	project, err := resolveProject(client, c.ProjectGID)
+	if err != nil {
+		return err
+	}
+
+	fields, err := taskColumns.parse(c.Fields)
+	if err != nil {
+		return err
+	}
+
+	optFields := ""
+	if c.Fields != "" {
+		optFields = taskColumns.optFields(fields)
+	}
 
-		created := "-"
-		if project.CreatedAt != "" {
-			created = project.CreatedAt[:10] // Just the date part
+	tasks, err := c.withOptFields(client).GetProjectTasks(project, c.IncludeCompleted, c.Limit, optFields)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(tasks)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found.")
+		return nil
+	}
+
+	t := taskColumns.table(tasks, fields)
+	if c.Limit > 0 && len(tasks) >= c.Limit {
+		t.footerf("(Showing %d tasks, use -l to increase limit)", c.Limit)
+	}
+	return t.print(g)
+}
+
+type ProjectsStatusCmd struct {
+	Post ProjectsStatusPostCmd `cmd:"" help:"Post a status update on a project"`
+	List ProjectsStatusListCmd `cmd:"" help:"List a project's status updates"`
+}
+
+type ProjectsStatusPostCmd struct {
+	ProjectGID string `arg:"" help:"Project GID or name"`
+	Color      string `required:"" enum:"green,yellow,red" help:"Status color: green (on track), yellow (at risk) or red (off track)"`
+	Text       string `required:"" help:"Status update text"`
+	Title      string `help:"Status update title"`
+	JSON       bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *ProjectsStatusPostCmd) Run(client *api.Client) error {
+	project, err := resolveProject(client, c.ProjectGID)
+	if err != nil {
+		return err
+	}
+
+	status, err := client.CreateProjectStatus(project, c.Title, c.Text, c.Color)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(status)
+	}
+
+	fmt.Printf("Status posted (%s)\n", status.Color)
+	fmt.Printf("GID: %s\n", status.GID)
+	return nil
+}
+
+type ProjectsStatusListCmd struct {
+	ProjectGID string `arg:"" help:"Project GID or name"`
+	Limit      int    `short:"l" default:"20" help:"Maximum number of status updates to return (0 for no limit)"`
+	JSON       bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *ProjectsStatusListCmd) Run(client *api.Client, g *Globals) error {
+	project, err := resolveProject(client, c.ProjectGID)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := client.ListProjectStatuses(project, c.Limit)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(statuses)
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No status updates found.")
+		return nil
+	}
+
+	t := newTable("GID", "DATE", "COLOR", "AUTHOR", "TEXT")
+	for _, s := range statuses {
+		author := "-"
+		if s.CreatedBy != nil {
+			author = s.CreatedBy.Name
 		}
+		text := s.Title
+		if text == "" {
+			text = strings.Join(strings.Fields(s.Text), " ")
+		}
+		t.row(s.GID, orDash(datePart(s.CreatedAt)), s.Color, author, truncate(text, 60))
+	}
 
-		name := truncate(project.Name, 40)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", project.GID, name, archived, created)
+	return t.print(g)
+}
+
+// ProjectsDuplicateCmd copies a project, e.g. to start the next quarter's
+// or client's project from a template project
+type ProjectsDuplicateCmd struct {
+	ProjectGID string        `arg:"" optional:"" help:"Project GID or name to duplicate"`
+	Name       string        `short:"n" help:"Name of the new project (defaults to \"Copy of <name>\")"`
+	Include    []string      `default:"members,notes,task_notes,task_assignee,task_subtasks,task_attachments,task_dependencies" help:"Parts to copy: members, notes, forms, allocations, task_notes, task_assignee, task_subtasks, task_attachments, task_dates, task_dependencies, task_followers, task_tags, task_projects"`
+	Team       string        `help:"Team GID or name for the new project (defaults to the original's team)"`
+	Timeout    time.Duration `default:"5m" help:"How long to wait for the duplication to finish"`
+	JSON       bool          `short:"j" help:"Output as JSON"`
+}
+
+func (c *ProjectsDuplicateCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickProject(client, g, &c.ProjectGID); err != nil {
+		return err
+	}
+	gid, err := resolveProject(client, c.ProjectGID)
+	if err != nil {
+		return err
+	}
+	team, err := resolveTeam(client, c.Team)
+	if err != nil {
+		return err
+	}
+
+	name := c.Name
+	if name == "" {
+		project, err := client.GetProject(gid)
+		if err != nil {
+			return notFound(err, "project", gid)
+		}
+		name = "Copy of " + project.Name
+	}
+
+	job, err := client.DuplicateProject(gid, name, c.Include, team)
+	if err != nil {
+		return notFound(err, "project", gid)
+	}
+
+	job, err = client.WaitForJob(job.GID, c.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(job)
+	}
+
+	fmt.Printf("Project duplicated: %s\n", name)
+	if job.NewProject != nil {
+		fmt.Printf("GID: %s\n", job.NewProject.GID)
 	}
+	return nil
+}
+
+// ProjectsCreateCmd creates a project from one of Asana's project
+// templates (see project-templates list)
+type ProjectsCreateCmd struct {
+	FromTemplate string        `required:"" placeholder:"TEMPLATE-GID" help:"Project template to create the project from"`
+	Name         string        `short:"n" required:"" help:"Name of the new project"`
+	Team         string        `short:"t" help:"Team GID or name for the new project (needed in organizations)"`
+	Privacy      string        `enum:",public_to_workspace,private_to_team,private" default:"" help:"Who can see the project: public_to_workspace, private_to_team or private (default: the template's setting)"`
+	Timeout      time.Duration `default:"5m" help:"How long to wait for the project to be created"`
+	JSON         bool          `short:"j" help:"Output as JSON"`
+}
 
-	w.Flush()
+func (c *ProjectsCreateCmd) Run(client *api.Client, g *Globals) error {
+	team, err := resolveTeam(client, c.Team)
+	if err != nil {
+		return err
+	}
+
+	job, err := client.InstantiateProjectTemplate(c.FromTemplate, c.Name, team, c.Privacy)
+	if err != nil {
+		return notFound(err, "project template", c.FromTemplate)
+	}
+
+	job, err = client.WaitForJob(job.GID, c.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(job)
+	}
+	if g.Quiet {
+		if job.NewProject != nil {
+			fmt.Println(job.NewProject.GID)
+		}
+		return nil
+	}
+
+	fmt.Printf("Project created: %s\n", c.Name)
+	if job.NewProject != nil {
+		fmt.Printf("GID: %s\n", job.NewProject.GID)
+	}
 	return nil
 }
+
+type ProjectsMembersCmd struct {
+	List   ProjectsMembersListCmd   `cmd:"" default:"withargs" help:"List a project's members (default)"`
+	Add    ProjectsMembersAddCmd    `cmd:"" help:"Add users to a project"`
+	Remove ProjectsMembersRemoveCmd `cmd:"" help:"Remove users from a project"`
+}
+
+type ProjectsMembersListCmd struct {
+	ProjectGID string `arg:"" help:"Project GID or name"`
+	JSON       bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *ProjectsMembersListCmd) Run(client *api.Client, g *Globals) error {
+	project, err := resolveProject(client, c.ProjectGID)
+	if err != nil {
+		return err
+	}
+
+	members, err := client.GetProjectMembers(project)
+	if err != nil {
+		return notFound(err, "project", project)
+	}
+
+	if c.JSON {
+		return printJSON(members)
+	}
+
+	if len(members) == 0 {
+		fmt.Println("No members found.")
+		return nil
+	}
+
+	t := newTable("GID", "NAME", "EMAIL", "ACCESS")
+	for _, m := range members {
+		if m.Member == nil {
+			continue
+		}
+		t.row(m.Member.GID, m.Member.Name, orDash(m.Member.Email), orDash(m.WriteAccess))
+	}
+
+	return t.print(g)
+}
+
+type ProjectsMembersAddCmd struct {
+	ProjectGID string   `arg:"" help:"Project GID or name"`
+	Users      []string `arg:"" help:"Users to add (GID, email, name, or 'me')"`
+}
+
+func (c *ProjectsMembersAddCmd) Run(client *api.Client) error {
+	project, users, err := resolveMembers(client, c.ProjectGID, c.Users)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.AddProjectMembers(project, users); err != nil {
+		return notFound(err, "project", project)
+	}
+
+	fmt.Printf("Added %d member(s) to project %s\n", len(users), project)
+	return nil
+}
+
+type ProjectsMembersRemoveCmd struct {
+	ProjectGID string   `arg:"" help:"Project GID or name"`
+	Users      []string `arg:"" help:"Users to remove (GID, email, name, or 'me')"`
+}
+
+func (c *ProjectsMembersRemoveCmd) Run(client *api.Client) error {
+	project, users, err := resolveMembers(client, c.ProjectGID, c.Users)
+	if err != nil {
+		return err
+	}
+
+	if _, err := client.RemoveProjectMembers(project, users); err != nil {
+		return notFound(err, "project", project)
+	}
+
+	fmt.Printf("Removed %d member(s) from project %s\n", len(users), project)
+	return nil
+}
+
+// resolveMembers resolves the project and user arguments of members add/remove
+func resolveMembers(client *api.Client, projectRef string, userRefs []string) (string, []string, error) {
+	project, err := resolveProject(client, projectRef)
+	if err != nil {
+		return "", nil, err
+	}
+
+	users := make([]string, len(userRefs))
+	for i, ref := range userRefs {
+		if users[i], err = resolveUser(client, ref); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return project, users, nil
+}
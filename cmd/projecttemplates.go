@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// ProjectTemplatesCmd works with Asana's project templates
+type ProjectTemplatesCmd struct {
+	List ProjectTemplatesListCmd `cmd:"" help:"List project templates"`
+}
+
+type ProjectTemplatesListCmd struct {
+	Team string `short:"t" help:"Only list the templates of this team (GID or name)"`
+	JSON bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *ProjectTemplatesListCmd) Run(client *api.Client, g *Globals) error {
+	team, err := resolveTeam(client, c.Team)
+	if err != nil {
+		return err
+	}
+
+	templates, err := client.ListProjectTemplates(team)
+	if err != nil {
+		return notFound(err, "team", team)
+	}
+
+	if c.JSON {
+		return printJSON(templates)
+	}
+
+	if len(templates) == 0 {
+		fmt.Println("No project templates found.")
+		return nil
+	}
+
+	t := newTable("GID", "NAME", "TEAM", "DESCRIPTION")
+	for _, pt := range templates {
+		teamName := "-"
+		if pt.Team != nil {
+			teamName = pt.Team.Name
+		}
+		t.row(pt.GID, truncate(pt.Name, 50), teamName, orDash(truncate(pt.Description, 60)))
+	}
+	t.footerf("Create a project with: asana projects create --from-template <gid> -n <name>")
+
+	return t.print(g)
+}
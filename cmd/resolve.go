@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// isGID reports whether s looks like an Asana GID (all digits)
+func isGID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// resolveUser turns a user reference into a value the API accepts.
+// GIDs are passed through unchanged and "me" becomes the current user's
+// GID; anything else is matched against the workspace users by email,
+// then by name (case-insensitive).
+func resolveUser(client *api.Client, ref string) (string, error) {
+	if ref == "" || isGID(ref) {
+		return ref, nil
+	}
+	if strings.EqualFold(ref, "me") {
+		return client.MyGID()
+	}
+
+	users, err := client.ListUsers("")
+	if err != nil {
+		return "", err
+	}
+
+	for _, u := range users {
+		if u.Email != "" && strings.EqualFold(u.Email, ref) {
+			return u.GID, nil
+		}
+	}
+
+	var matches []api.User
+	for _, u := range users {
+		if strings.EqualFold(u.Name, ref) {
+			matches = append(matches, u)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no user found matching %q", ref)
+	case 1:
+		return matches[0].GID, nil
+	default:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "multiple users match %q, use a GID instead:", ref)
+		for _, u := range matches {
+			fmt.Fprintf(&sb, "\n  %s  %s", u.GID, u.Name)
+		}
+		return "", fmt.Errorf("%s", sb.String())
+	}
+}
+
+// ResolveWorkspace turns a workspace reference (GID or name) into a
+// workspace GID. Names are matched case-insensitively.
+func ResolveWorkspace(client *api.Client, ref string) (string, error) {
+	if isGID(ref) {
+		return ref, nil
+	}
+
+	workspaces, err := client.ListWorkspaces()
+	if err != nil {
+		return "", err
+	}
+
+	for _, w := range workspaces {
+		if strings.EqualFold(w.Name, ref) {
+			return w.GID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no workspace found matching %q (run 'asana workspaces list')", ref)
+}
+
+// resolveProject turns a project reference (GID or name) into a project GID.
+// Names are matched case-insensitively against the workspace's active projects.
+func resolveProject(client *api.Client, ref string) (string, error) {
+	if ref == "" || isGID(ref) {
+		return ref, nil
+	}
+
+	projects, _, err := client.ListProjects(api.ActiveProjects, 100, "", "")
+	if err != nil {
+		return "", err
+	}
+
+	var matches []api.Project
+	for _, p := range projects {
+		if strings.EqualFold(p.Name, ref) {
+			matches = append(matches, p)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no project found matching %q", ref)
+	case 1:
+		return matches[0].GID, nil
+	default:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "multiple projects match %q, use a GID instead:", ref)
+		for _, p := range matches {
+			fmt.Fprintf(&sb, "\n  %s  %s", p.GID, p.Name)
+		}
+		return "", fmt.Errorf("%s", sb.String())
+	}
+}
+
+// resolveTeam turns a team reference into a team GID. GIDs are passed
+// through unchanged; names are matched case-insensitively against the
+// workspace's teams.
+func resolveTeam(client *api.Client, ref string) (string, error) {
+	if ref == "" || isGID(ref) {
+		return ref, nil
+	}
+
+	teams, err := client.ListTeams(false)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []api.Team
+	for _, t := range teams {
+		if strings.EqualFold(t.Name, ref) {
+			matches = append(matches, t)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no team found matching %q", ref)
+	case 1:
+		return matches[0].GID, nil
+	default:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "multiple teams match %q, use a GID instead:", ref)
+		for _, t := range matches {
+			fmt.Fprintf(&sb, "\n  %s  %s", t.GID, t.Name)
+		}
+		return "", fmt.Errorf("%s", sb.String())
+	}
+}
+
+// resolveTag turns a tag name into a tag GID. Names are matched
+// case-insensitively against the workspace's tags.
+func resolveTag(client *api.Client, name string) (string, error) {
+	tags, err := client.ListTags()
+	if err != nil {
+		return "", err
+	}
+
+	var matches []api.Tag
+	for _, t := range tags {
+		if strings.EqualFold(t.Name, name) {
+			matches = append(matches, t)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no tag found matching %q", name)
+	case 1:
+		return matches[0].GID, nil
+	default:
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "multiple tags match %q, use the tag's GID instead:", name)
+		for _, t := range matches {
+			fmt.Fprintf(&sb, "\n  %s  %s", t.GID, t.Name)
+		}
+		return "", fmt.Errorf("%s", sb.String())
+	}
+}
+
+// notFound turns a 404 from the API into a message naming the missing
+// object. Other errors are returned unchanged.
+func notFound(err error, kind, gid string) error {
+	if errors.Is(err, api.ErrNotFound) {
+		return fmt.Errorf("%s %s not found", kind, gid)
+	}
+	return err
+}
+
+// resolveTaskName finds the task whose name is name (case-insensitive)
+// among typeahead results. Several matches are offered in the picker with
+// -i and listed otherwise, so the wrong task is never picked silently.
+func resolveTaskName(client *api.Client, g *Globals, name string) (string, error) {
+	results, err := client.Typeahead(name, "task", 100)
+	if err != nil {
+		return "", err
+	}
+
+	var matches []api.TypeaheadResult
+	for _, r := range results {
+		if strings.EqualFold(r.Name, name) {
+			matches = append(matches, r)
+		}
+	}
+
+	switch {
+	case len(matches) == 1:
+		return matches[0].GID, nil
+	case len(matches) > 1 && canPick(g):
+		items := make([]pickerItem, len(matches))
+		for i, m := range matches {
+			items[i] = pickerItem{GID: m.GID, Label: m.Name + "  (" + m.GID + ")"}
+		}
+		return pick(g, "task", items)
+	}
+
+	var sb strings.Builder
+	if len(matches) == 0 {
+		fmt.Fprintf(&sb, "no task named %q", name)
+		if len(results) == 0 {
+			return "", fmt.Errorf("%s", sb.String())
+		}
+		sb.WriteString(", similar tasks:")
+		matches = results
+	} else {
+		fmt.Fprintf(&sb, "multiple tasks are named %q, use a GID (or -i) instead:", name)
+	}
+	for _, m := range matches {
+		fmt.Fprintf(&sb, "\n  %s  %s", m.GID, m.Name)
+	}
+	return "", fmt.Errorf("%s", sb.String())
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// SectionsCmd works with the sections (board columns) of a project
+type SectionsCmd struct {
+	List   SectionsListCmd   `cmd:"" help:"List a project's sections in order"`
+	Rename SectionsRenameCmd `cmd:"" help:"Rename a section"`
+	Move   SectionsMoveCmd   `cmd:"" help:"Move a section before or after another one"`
+}
+
+type SectionsListCmd struct {
+	Project string `arg:"" help:"Project GID or name"`
+	JSON    bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *SectionsListCmd) Run(client *api.Client, g *Globals) error {
+	project, err := resolveProject(client, c.Project)
+	if err != nil {
+		return err
+	}
+
+	sections, err := client.ListSections(project)
+	if err != nil {
+		return notFound(err, "project", c.Project)
+	}
+
+	if c.JSON {
+		if sections == nil {
+			sections = []api.Section{}
+		}
+		return printJSON(sections)
+	}
+
+	if len(sections) == 0 {
+		fmt.Println("No sections found.")
+		return nil
+	}
+
+	t := newTable("GID", "NAME")
+	for _, s := range sections {
+		t.row(s.GID, s.Name)
+	}
+	return t.print(g)
+}
+
+type SectionsRenameCmd struct {
+	SectionGID string `arg:"" help:"Section GID to rename"`
+	Name       string `arg:"" help:"New section name"`
+}
+
+func (c *SectionsRenameCmd) Run(client *api.Client, g *Globals) error {
+	section, err := client.UpdateSection(c.SectionGID, c.Name)
+	if err != nil {
+		return notFound(err, "section", c.SectionGID)
+	}
+
+	if g.Quiet {
+		fmt.Println(section.GID)
+		return nil
+	}
+	fmt.Printf("Section %s renamed to %q.\n", section.GID, section.Name)
+	return nil
+}
+
+// SectionsMoveCmd moves a section within its project. Asana places it
+// relative to another section, so one of --before and --after is needed.
+type SectionsMoveCmd struct {
+	SectionGID string `arg:"" help:"Section GID to move"`
+	Before     string `help:"Place the section just before this section GID" xor:"position"`
+	After      string `help:"Place the section just after this section GID" xor:"position"`
+}
+
+func (c *SectionsMoveCmd) Run(client *api.Client, g *Globals) error {
+	ref := c.Before + c.After
+	switch ref {
+	case "":
+		return fmt.Errorf("give --before or --after to say where the section goes")
+	case c.SectionGID:
+		return fmt.Errorf("a section can't be placed relative to itself")
+	}
+
+	// Both sections must be in the same project, which the insert endpoint
+	// needs anyway
+	var section, other *api.Section
+	err := runParallel(
+		func(ctx context.Context) (err error) {
+			section, err = client.WithContext(ctx).GetSection(c.SectionGID)
+			return notFound(err, "section", c.SectionGID)
+		},
+		func(ctx context.Context) (err error) {
+			other, err = client.WithContext(ctx).GetSection(ref)
+			return notFound(err, "section", ref)
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if section.Project == nil {
+		return fmt.Errorf("section %s does not belong to a project", c.SectionGID)
+	}
+	if other.Project == nil || other.Project.GID != section.Project.GID {
+		return fmt.Errorf("section %s is not in project %q, which section %q belongs to", ref, section.Project.Name, section.Name)
+	}
+
+	if err := client.InsertSection(section.Project.GID, section.GID, c.Before, c.After); err != nil {
+		return err
+	}
+
+	switch {
+	case g.Quiet:
+		fmt.Println(section.GID)
+	case c.Before != "":
+		fmt.Printf("Section %q moved before %q.\n", section.Name, other.Name)
+	default:
+		fmt.Printf("Section %q moved after %q.\n", section.Name, other.Name)
+	}
+	return nil
+}
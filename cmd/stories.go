@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// StoriesCmd works with the stories (comments and activity) on a task
+type StoriesCmd struct {
+	Pin   StoriesPinCmd   `cmd:"" help:"Pin a comment to the top of its task"`
+	Unpin StoriesUnpinCmd `cmd:"" help:"Unpin a comment"`
+}
+
+type StoriesPinCmd struct {
+	StoryGID string `arg:"" help:"Comment/story GID to pin"`
+}
+
+func (c *StoriesPinCmd) Run(client *api.Client, g *Globals) error {
+	return setPinned(client, g, c.StoryGID, true)
+}
+
+type StoriesUnpinCmd struct {
+	StoryGID string `arg:"" help:"Comment/story GID to unpin"`
+}
+
+func (c *StoriesUnpinCmd) Run(client *api.Client, g *Globals) error {
+	return setPinned(client, g, c.StoryGID, false)
+}
+
+func setPinned(client *api.Client, g *Globals, gid string, pinned bool) error {
+	if _, err := client.SetStoryPinned(gid, pinned); err != nil {
+		return notFound(err, "comment", gid)
+	}
+
+	switch {
+	case g.Quiet:
+		fmt.Println(gid)
+	case pinned:
+		fmt.Printf("Comment %s pinned.\n", gid)
+	default:
+		fmt.Printf("Comment %s unpinned.\n", gid)
+	}
+	return nil
+}
+
+// storyIcons prefixes activity stories by resource_subtype. Subtypes not
+// listed here get a plain bullet.
+var storyIcons = map[string]string{
+	"assigned":             "→",
+	"unassigned":           "→",
+	"due_date_changed":     "◷",
+	"start_date_changed":   "◷",
+	"added_to_project":     "+",
+	"removed_from_project": "−",
+	"section_changed":      "⇄",
+	"marked_complete":      "✓",
+	"marked_incomplete":    "↺",
+	"name_changed":         "✎",
+	"notes_changed":        "✎",
+	"added_to_tag":         "#",
+	"removed_from_tag":     "#",
+	"attachment_added":     "⎘",
+	"liked":                "♥",
+	"unliked":              "♥",
+}
+
+// printStory prints a comment as a header with its text indented below,
+// and any other story as a single icon-prefixed activity line
+func printStory(story api.Story) {
+	author := "Unknown"
+	if story.CreatedBy != nil {
+		author = story.CreatedBy.Name
+	}
+
+	if isComment(story) {
+		pin := ""
+		if story.IsPinned {
+			pin = " 📌"
+		}
+		fmt.Printf("[%s] %s commented:%s\n", formatTime(story.CreatedAt), author, pin)
+		for _, line := range strings.Split(story.Text, "\n") {
+			fmt.Printf("  %s\n", line)
+		}
+		fmt.Println()
+		return
+	}
+
+	fmt.Printf("[%s] %s %s %s\n", formatTime(story.CreatedAt), storyIcon(story), author, activityText(story))
+}
+
+// isComment reports whether a story is a user comment rather than activity
+func isComment(story api.Story) bool {
+	return story.ResourceSubtype == "comment_added" || story.Type == "comment"
+}
+
+// storyIcon returns the marker for an activity story
+func storyIcon(story api.Story) string {
+	if icon, ok := storyIcons[story.ResourceSubtype]; ok {
+		return icon
+	}
+	return "•"
+}
+
+// activityText describes an activity story, falling back to its subtype
+// when the API sent no text
+func activityText(story api.Story) string {
+	if story.Text != "" {
+		return story.Text
+	}
+	if story.ResourceSubtype != "" {
+		return strings.ReplaceAll(story.ResourceSubtype, "_", " ")
+	}
+	return "updated the task"
+}
@@ -1,20 +1,21 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
-	"os"
 	"sort"
-	"text/tabwriter"
 
 	"github.com/mauricejumelet/asana-cli/internal/api"
 )
 
 type SummaryCmd struct {
 	Project string `short:"p" help:"Filter by project GID"`
+	Chart   bool   `help:"Show the counts as bar charts"`
+	Top     int    `default:"10" help:"Number of assignees to chart with --chart"`
 	JSON    bool   `short:"j" help:"Output as JSON"`
 }
 
-func (c *SummaryCmd) Run(client *api.Client) error {
+func (c *SummaryCmd) Run(client *api.Client, g *Globals) error {
 	summary, err := client.GetTaskSummary(c.Project)
 	if err != nil {
 		return err
@@ -23,6 +24,9 @@ func (c *SummaryCmd) Run(client *api.Client) error {
 	if c.JSON {
 		return printJSON(summary)
 	}
+	if c.Chart {
+		return c.printChart(summary, g)
+	}
 
 	fmt.Println("Task Summary")
 	fmt.Println("============")
@@ -31,6 +35,13 @@ func (c *SummaryCmd) Run(client *api.Client) error {
 	fmt.Printf("Completed Tasks: %d\n", summary.CompletedTasks)
 	fmt.Printf("Overdue Tasks:   %d\n", summary.OverdueTasks)
 	fmt.Printf("Unassigned:      %d\n", summary.Unassigned)
+	if summary.Unassigned > 0 {
+		hint := "asana tasks list --unassigned"
+		if c.Project != "" {
+			hint += " -p " + c.Project
+		}
+		fmt.Printf("                 (list them with: %s)\n", hint)
+	}
 
 	if summary.TotalTasks >= 100 {
 		fmt.Println("\n(Note: Results limited to 100 tasks)")
@@ -40,27 +51,72 @@ func (c *SummaryCmd) Run(client *api.Client) error {
 		fmt.Println("\nTasks by Assignee")
 		fmt.Println("-----------------")
 
-		// Sort assignees by task count (descending)
-		type assigneeCount struct {
-			Name  string
-			Count int
+		t := newTable("ASSIGNEE", "TASKS")
+		for _, ac := range byCount(summary.ByAssignee) {
+			t.row(ac.Name, fmt.Sprintf("%d", ac.Count))
 		}
-		var sorted []assigneeCount
-		for name, count := range summary.ByAssignee {
-			sorted = append(sorted, assigneeCount{name, count})
+		return t.print(g)
+	}
+
+	return nil
+}
+
+// printChart shows the summary as bar charts sized to the terminal
+func (c *SummaryCmd) printChart(summary *api.TaskSummary, g *Globals) error {
+	cols, _ := terminalSize()
+	if cols <= 0 {
+		cols = defaultCols
+	}
+	color := useColor(g)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Tasks (%d)\n", summary.TotalTasks)
+	barChart(&buf, []bar{
+		{"Open", summary.OpenTasks, colorBlue},
+		{"Completed", summary.CompletedTasks, colorGreen},
+		{"Overdue", summary.OverdueTasks, colorRed},
+		{"Unassigned", summary.Unassigned, ""},
+	}, cols, color)
+
+	if len(summary.ByAssignee) > 0 {
+		sorted := byCount(summary.ByAssignee)
+		title := "By Assignee"
+		if c.Top > 0 && len(sorted) > c.Top {
+			title = fmt.Sprintf("Top %d Assignees", c.Top)
+			sorted = sorted[:c.Top]
 		}
-		sort.Slice(sorted, func(i, j int) bool {
-			return sorted[i].Count > sorted[j].Count
-		})
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-		fmt.Fprintln(w, "ASSIGNEE\tTASKS")
-		fmt.Fprintln(w, "--------\t-----")
-		for _, ac := range sorted {
-			fmt.Fprintf(w, "%s\t%d\n", ac.Name, ac.Count)
+		bars := make([]bar, len(sorted))
+		for i, ac := range sorted {
+			bars[i] = bar{truncate(ac.Name, 24), ac.Count, colorCyan}
 		}
-		w.Flush()
+		fmt.Fprintf(&buf, "\n%s\n", title)
+		barChart(&buf, bars, cols, color)
 	}
 
-	return nil
+	if summary.TotalTasks >= 100 {
+		buf.WriteString("\n(Note: Results limited to 100 tasks)\n")
+	}
+
+	return pageOutput(g, buf.Bytes())
+}
+
+type assigneeCount struct {
+	Name  string
+	Count int
+}
+
+// byCount sorts assignees by task count (descending), then by name
+func byCount(counts map[string]int) []assigneeCount {
+	var sorted []assigneeCount
+	for name, count := range counts {
+		sorted = append(sorted, assigneeCount{name, count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
 }
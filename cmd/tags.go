@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+type TagsCmd struct {
+	Tasks TagsTasksCmd `cmd:"" help:"List tasks with a tag"`
+}
+
+// TagsTasksCmd lists a tag's tasks straight from the tag, which is cheaper
+// than a search and keeps the tag's manual order
+type TagsTasksCmd struct {
+	Tag              string `arg:"" help:"Tag GID or name"`
+	IncludeCompleted bool   `help:"Include completed tasks"`
+	Limit            int    `short:"l" default:"100" help:"Maximum number of tasks to return (0 for no limit)"`
+	Fields           string `help:"Comma-separated columns to show (gid,name,due,age,assignee,project,projects,tags,completed,completed_on,created,modified,permalink)"`
+	JSON             bool   `short:"j" help:"Output as JSON"`
+	OptFieldsFlags
+}
+
+func (c *TagsTasksCmd) Run(client *api.Client, g *Globals) error {
+	tag := c.Tag
+	if !isGID(tag) {
+		var err error
+		if tag, err = resolveTag(client, tag); err != nil {
+			return err
+		}
+	}
+
+	fields, err := taskColumns.parse(c.Fields)
+	if err != nil {
+		return err
+	}
+
+	optFields := ""
+	if c.Fields != "" {
+		optFields = taskColumns.optFields(fields)
+	}
+
+	tasks, err := c.withOptFields(client).GetTagTasks(tag, c.IncludeCompleted, c.Limit, optFields)
+	if err != nil {
+		return notFound(err, "tag", c.Tag)
+	}
+
+	if c.JSON {
+		return printJSON(tasks)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found.")
+		return nil
+	}
+
+	t := taskColumns.table(tasks, fields)
+	if c.Limit > 0 && len(tasks) >= c.Limit {
+		t.footerf("(Showing %d tasks, use -l to increase limit)", c.Limit)
+	}
+	return t.print(g)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// diffFields are the fields tasks get --diff compares, in display order
+var diffFields = []string{"name", "assignee", "due", "projects", "tags", "notes"}
+
+// diffValue returns a task's value for one of diffFields, normalized so
+// that equal values compare equal (e.g. projects in any order)
+func diffValue(t *api.Task, field string) string {
+	switch field {
+	case "name":
+		return t.Name
+	case "assignee":
+		if t.Assignee != nil {
+			return t.Assignee.Name
+		}
+	case "due":
+		if t.DueAt != "" {
+			return formatTime(t.DueAt)
+		}
+		return t.DueOn
+	case "projects":
+		names := make([]string, len(t.Projects))
+		for i, p := range t.Projects {
+			names[i] = p.Name
+		}
+		sort.Strings(names)
+		return strings.Join(names, ", ")
+	case "tags":
+		names := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			names[i] = tag.Name
+		}
+		sort.Strings(names)
+		return strings.Join(names, ", ")
+	case "notes":
+		return strings.TrimSpace(t.Notes)
+	}
+	return ""
+}
+
+// printTaskDiff fetches two tasks and prints their fields side by side,
+// marking the ones that differ
+func printTaskDiff(client *api.Client, g *Globals, gidA, gidB string, asJSON bool) error {
+	var a, b *api.Task
+	err := runParallel(
+		func(ctx context.Context) (err error) {
+			if a, err = client.WithContext(ctx).GetTask(gidA); err != nil {
+				return notFound(err, "task", gidA)
+			}
+			return nil
+		},
+		func(ctx context.Context) (err error) {
+			if b, err = client.WithContext(ctx).GetTask(gidB); err != nil {
+				return notFound(err, "task", gidB)
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	differs := []string{}
+	for _, f := range diffFields {
+		if diffValue(a, f) != diffValue(b, f) {
+			differs = append(differs, f)
+		}
+	}
+
+	if asJSON {
+		return printJSON(map[string]interface{}{
+			"a":       a,
+			"b":       b,
+			"differs": differs,
+		})
+	}
+
+	color := useColor(g)
+	t := newTable("", "FIELD", gidA, gidB)
+	for _, f := range diffFields {
+		va, vb := orDash(diffValue(a, f)), orDash(diffValue(b, f))
+		if f == "notes" {
+			va = truncate(strings.Join(strings.Fields(va), " "), 40)
+			vb = truncate(strings.Join(strings.Fields(vb), " "), 40)
+		}
+
+		mark := ""
+		if diffValue(a, f) != diffValue(b, f) {
+			mark = "≠"
+			if color {
+				va, vb = colorRed+va+colorReset, colorGreen+vb+colorReset
+			}
+		}
+		t.row(mark, f, va, vb)
+	}
+
+	if len(differs) == 0 {
+		t.footerf("The tasks match on all %d fields.", len(diffFields))
+	} else {
+		t.footerf("%d of %d fields differ: %s", len(differs), len(diffFields), strings.Join(differs, ", "))
+	}
+	return t.print(g)
+}
@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// taskReport is the data rendered by taskReportTemplate
+type taskReport struct {
+	Task        *api.Task
+	Status      string
+	Notes       template.HTML
+	Projects    []string
+	Tags        []string
+	Subtasks    []api.Task
+	Done        int
+	Attachments []api.Attachment
+	Stories     []reportStory
+}
+
+type reportStory struct {
+	Time    string
+	Author  string
+	Comment bool
+	Icon    string
+	Text    template.HTML
+}
+
+// taskReportTemplate is a standalone page: styles are inline and nothing
+// is loaded from elsewhere, so the file can be mailed or attached as is
+var taskReportTemplate = template.Must(template.New("task").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Task.Name}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; color: #1e1f21; line-height: 1.5; }
+h1 { font-size: 1.6rem; margin-bottom: .25rem; }
+h2 { font-size: 1.1rem; margin-top: 2rem; border-bottom: 1px solid #e0e0e0; padding-bottom: .25rem; }
+table.meta { border-collapse: collapse; }
+table.meta th { text-align: left; color: #6d6e6f; font-weight: normal; padding: .15rem 1.5rem .15rem 0; vertical-align: top; }
+.status { display: inline-block; padding: 0 .5rem; border-radius: .75rem; background: #e8f0fe; font-size: .85rem; }
+.status.done { background: #e3f5ec; }
+.notes { white-space: pre-wrap; }
+.story { margin: .75rem 0; }
+.story .when { color: #6d6e6f; font-size: .85rem; }
+.comment .text { white-space: pre-wrap; margin-top: .25rem; padding: .5rem .75rem; background: #f6f7f8; border-radius: .25rem; }
+.activity { color: #6d6e6f; font-size: .9rem; }
+ul.subtasks { list-style: none; padding-left: 0; }
+footer { margin-top: 3rem; color: #9ca0a4; font-size: .8rem; }
+</style>
+</head>
+<body>
+<h1>{{.Task.Name}}</h1>
+<span class="status{{if .Task.Completed}} done{{end}}">{{.Status}}</span>
+
+<h2>Details</h2>
+<table class="meta">
+<tr><th>GID</th><td>{{.Task.GID}}</td></tr>
+{{- if .Task.Assignee}}
+<tr><th>Assignee</th><td>{{.Task.Assignee.Name}}{{if .Task.Assignee.Email}} &lt;{{.Task.Assignee.Email}}&gt;{{end}}</td></tr>
+{{- end}}
+{{- if .Task.StartOn}}
+<tr><th>Start</th><td>{{.Task.StartOn}}</td></tr>
+{{- end}}
+{{- if .Task.DueOn}}
+<tr><th>Due</th><td>{{.Task.DueOn}}</td></tr>
+{{- end}}
+{{- if .Projects}}
+<tr><th>Projects</th><td>{{range $i, $p := .Projects}}{{if $i}}, {{end}}{{$p}}{{end}}</td></tr>
+{{- end}}
+{{- if .Tags}}
+<tr><th>Tags</th><td>{{range $i, $t := .Tags}}{{if $i}}, {{end}}{{$t}}{{end}}</td></tr>
+{{- end}}
+{{- if .Subtasks}}
+<tr><th>Subtasks</th><td>{{.Done}}/{{len .Subtasks}} complete</td></tr>
+{{- end}}
+{{- if .Task.Permalink}}
+<tr><th>Link</th><td><a href="{{.Task.Permalink}}">{{.Task.Permalink}}</a></td></tr>
+{{- end}}
+</table>
+{{- if .Notes}}
+
+<h2>Description</h2>
+<div class="notes">{{.Notes}}</div>
+{{- end}}
+{{- if .Subtasks}}
+
+<h2>Subtasks</h2>
+<ul class="subtasks">
+{{- range .Subtasks}}
+<li>{{if .Completed}}&#9745;{{else}}&#9744;{{end}} {{.Name}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Attachments}}
+
+<h2>Attachments</h2>
+<ul>
+{{- range .Attachments}}
+<li>{{if .PermanentURL}}<a href="{{.PermanentURL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Stories}}
+
+<h2>Comments &amp; Activity</h2>
+{{- range .Stories}}
+{{- if .Comment}}
+<div class="story comment"><strong>{{.Author}}</strong> <span class="when">{{.Time}}</span><div class="text">{{.Text}}</div></div>
+{{- else}}
+<div class="story activity">{{.Icon}} {{.Author}} {{.Text}} <span class="when">{{.Time}}</span></div>
+{{- end}}
+{{- end}}
+{{- end}}
+
+<footer>Exported from Asana with asana-cli</footer>
+</body>
+</html>
+`))
+
+// writeTaskHTML renders a task, its subtasks, attachments and stories as a
+// self-contained HTML page
+func writeTaskHTML(w io.Writer, task *api.Task, subtasks []api.Task, attachments []api.Attachment, stories []api.Story) error {
+	r := taskReport{
+		Task:        task,
+		Status:      statusString(task.Completed),
+		Subtasks:    subtasks,
+		Attachments: attachments,
+	}
+
+	// Asana's rich text is a small, sanitized subset of HTML, so it is
+	// embedded as is; plain text is escaped
+	if task.HTMLNotes != "" {
+		r.Notes = template.HTML(richTextBody(task.HTMLNotes))
+	} else if task.Notes != "" {
+		r.Notes = template.HTML(template.HTMLEscapeString(task.Notes))
+	}
+	for _, p := range task.Projects {
+		r.Projects = append(r.Projects, p.Name)
+	}
+	for _, t := range task.Tags {
+		r.Tags = append(r.Tags, t.Name)
+	}
+	for _, st := range subtasks {
+		if st.Completed {
+			r.Done++
+		}
+	}
+
+	for _, s := range stories {
+		rs := reportStory{Time: formatTime(s.CreatedAt), Author: "Unknown"}
+		if s.CreatedBy != nil {
+			rs.Author = s.CreatedBy.Name
+		}
+		rs.Comment = isComment(s)
+		switch {
+		case !rs.Comment:
+			rs.Icon = storyIcon(s)
+			rs.Text = template.HTML(template.HTMLEscapeString(activityText(s)))
+		case s.HTMLText != "":
+			rs.Text = template.HTML(richTextBody(s.HTMLText))
+		default:
+			rs.Text = template.HTML(template.HTMLEscapeString(s.Text))
+		}
+		r.Stories = append(r.Stories, rs)
+	}
+
+	if err := taskReportTemplate.Execute(w, r); err != nil {
+		return fmt.Errorf("writing HTML: %w", err)
+	}
+	return nil
+}
+
+// richTextBody strips the <body> wrapper from Asana rich text
+func richTextBody(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "<body>")
+	return strings.TrimSuffix(s, "</body>")
+}
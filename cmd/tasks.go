@@ -1,12 +1,23 @@
 package cmd
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"html"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
-	"text/tabwriter"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/alecthomas/kong"
 	"github.com/mauricejumelet/asana-cli/internal/api"
+	"github.com/mauricejumelet/asana-cli/internal/config"
 )
 
 type TasksCmd struct {
@@ -16,7 +27,17 @@ type TasksCmd struct {
 	Complete TasksCompleteCmd `cmd:"" help:"Mark a task as complete"`
 	Reopen   TasksReopenCmd   `cmd:"" help:"Reopen a completed task"`
 	Update   TasksUpdateCmd   `cmd:"" help:"Update a task"`
+	BulkUpdate TasksBulkUpdateCmd `cmd:"" help:"Update many tasks from a JSON file"`
+	Assign   TasksAssignCmd   `cmd:"" help:"Assign a task to a user"`
+	Unassign TasksUnassignCmd `cmd:"" help:"Remove the assignee from a task"`
+	Like     TasksLikeCmd     `cmd:"" help:"Like a task"`
+	Unlike   TasksUnlikeCmd   `cmd:"" help:"Remove your like from a task"`
+	Time     TasksTimeCmd     `cmd:"" aliases:"add-time" help:"Show or add time tracked on a task"`
+	Reorder     TasksReorderCmd     `cmd:"" aliases:"move-to-section" help:"Move a task within or into a project section"`
+	SetParent   TasksSetParentCmd   `cmd:"" help:"Make a task a subtask of another task"`
+	UnsetParent TasksUnsetParentCmd `cmd:"" help:"Turn a subtask into a top-level task"`
 	Delete   TasksDeleteCmd   `cmd:"" help:"Delete a task"`
+	Duplicate TasksDuplicateCmd `cmd:"" help:"Duplicate a task"`
 	Comment   TasksCommentCmd   `cmd:"" help:"Add a comment to a task"`
 	Uncomment TasksUncommentCmd `cmd:"" help:"Delete a comment from a task"`
 	Search    TasksSearchCmd    `cmd:"" help:"Search for tasks"`
@@ -24,43 +45,183 @@ type TasksCmd struct {
 
 type TasksListCmd struct {
 	// Shortcut flags
-	Mine bool `short:"m" help:"Show only tasks assigned to me (shortcut for -a me)"`
+	Mine bool `short:"m" help:"Show only tasks assigned to me (shortcut for -a me)" xor:"assignee"`
 
 	// Filter flags
 	Project  string `short:"p" help:"Filter by project GID or name"`
-	Assignee string `short:"a" help:"Filter by assignee GID (use 'me' for yourself)"`
-	Tag      string `short:"t" help:"Filter by tag GID"`
-	Due      string `short:"d" help:"Filter by due date: today, tomorrow, week, overdue, or YYYY-MM-DD"`
+	Assignee string `short:"a" help:"Filter by assignee GID (use 'me' for yourself)" xor:"assignee"`
+	Unassigned bool `aliases:"no-assignee" help:"Show only tasks without an assignee" xor:"assignee"`
+	Assigned   bool `help:"Show only tasks that have an assignee" xor:"assignee"`
+	Tag      string `short:"t" help:"Filter by tag GID" xor:"tag"`
+	TagName  string `help:"Filter by tag name" xor:"tag"`
+	Due      string `short:"d" help:"Filter by due date: today, tomorrow, week, overdue, or YYYY-MM-DD" xor:"due,min-due,max-due"`
+	OverdueDays int `help:"Show only tasks overdue by more than N days" placeholder:"N" xor:"due,min-due,max-due"`
+	MinDue   string `placeholder:"YYYY-MM-DD" help:"Show only tasks due on or after a date" xor:"min-due"`
+	MaxDue   string `placeholder:"YYYY-MM-DD" help:"Show only tasks due on or before a date" xor:"max-due"`
+	ModifiedAfter string `aliases:"since" help:"Show only tasks modified after a date (YYYY-MM-DD) or relative time (7d, 24h)"`
+	CreatedAfter  string `help:"Show only tasks created after a date (YYYY-MM-DD) or relative time (7d, 24h)"`
+	CompletedSince string `help:"Show only tasks completed after a date (YYYY-MM-DD) or relative time (7d, 24h)"`
 
 	// Display flags
-	All   bool `help:"Include completed tasks"`
-	Limit int  `short:"l" default:"100" help:"Maximum number of tasks to return"`
-	Sort  string `short:"s" default:"due_date" help:"Sort by: due_date, created_at, modified_at"`
-	JSON  bool `short:"j" help:"Output as JSON"`
+	All   bool `help:"Include completed tasks" xor:"completed"`
+	CompletedOnly bool `help:"Show only completed tasks" xor:"completed"`
+	IncludeSubtasks bool `help:"Include subtasks (marked with ↳)"`
+	Limit int  `short:"l" default:"100" help:"Maximum number of tasks to return (0 for no limit)"`
+	Sort  string `short:"s" default:"due_date" enum:"due_date,created_at,modified_at,completed_at,likes,name" help:"Sort by: due_date, created_at, modified_at, completed_at, likes, name (name fetches every matching task)"`
+	Desc  bool   `help:"Sort in descending order"`
+	NullsFirst bool `help:"With --sort due_date, list tasks without a due date first instead of last"`
+	Fields string `help:"Comma-separated columns to show (gid,name,due,age,assignee,project,projects,tags,completed,completed_on,created,modified,permalink)"`
+	ShowAge bool `help:"Add an AGE column showing how many days each task is overdue"`
+	Format string `default:"table" enum:"table,board" help:"Output format: table, or board to group a project's tasks by section (needs -p)"`
+	GroupBy string `default:"none" enum:"none,section" help:"Group the table by: none, or section (needs -p)"`
+	Width  int    `default:"24" help:"Column width for --format board"`
+	JSON  bool `short:"j" help:"Output as JSON" xor:"output"`
+	JSONMeta bool `help:"Output as JSON wrapped with count and workspace" xor:"output"`
+	JSONL    bool `name:"jsonl" aliases:"ndjson" help:"Output one JSON task per line, streamed as pages arrive" xor:"output"`
+	PermalinkOnly bool `help:"Print only the tasks' URLs, one per line" xor:"output"`
+	CountOnly bool `help:"Print only the number of matching tasks, counting every page (--limit is ignored)" xor:"output"`
+	OptFieldsFlags
 }
 
-func (c *TasksListCmd) Run(client *api.Client) error {
+func (c *TasksListCmd) Run(client *api.Client, g *Globals, cfg *config.Config) error {
+	defaultProject, defaultAssignee := g.defaults(cfg)
+	if c.Project == "" {
+		c.Project = defaultProject
+	}
+	if c.Assignee == "" && !c.Mine && !c.Unassigned && !c.Assigned {
+		c.Assignee = defaultAssignee
+	}
+
+	fields, err := taskColumns.parse(c.Fields)
+	if err != nil {
+		return err
+	}
+	if c.ShowAge && !hasField(fields, "age") {
+		fields = append(fields, "age")
+	}
+	if c.CompletedSince != "" && !hasField(fields, "completed_on") {
+		fields = append(fields, "completed_on")
+	}
+
 	// Handle --mine shortcut
 	assignee := c.Assignee
 	if c.Mine {
 		assignee = "me"
 	}
+	assignee, err = resolveUser(client, assignee)
+	if err != nil {
+		return err
+	}
+
+	project, err := resolveProject(client, c.Project)
+	if err != nil {
+		return err
+	}
+
+	if c.Format == "board" {
+		if project == "" {
+			return fmt.Errorf("--format board needs a project (-p)")
+		}
+		return printBoard(client, g, project, c.All, c.Limit, c.Width)
+	}
+	if c.GroupBy == "section" && project == "" {
+		return fmt.Errorf("--group-by section needs a project (-p)")
+	}
+
+	dueAfter, dueBefore, err := dueRange(c.MinDue, c.MaxDue)
+	if err != nil {
+		return err
+	}
+	modifiedAfter, err := parseSince(c.ModifiedAfter, time.Now())
+	if err != nil {
+		return fmt.Errorf("--modified-after: %w", err)
+	}
+	createdAfter, err := parseSince(c.CreatedAfter, time.Now())
+	if err != nil {
+		return fmt.Errorf("--created-after: %w", err)
+	}
+	completedSince, err := parseSince(c.CompletedSince, time.Now())
+	if err != nil {
+		return fmt.Errorf("--completed-since: %w", err)
+	}
+
+	tag := c.Tag
+	if c.TagName != "" {
+		tag, err = resolveTag(client, c.TagName)
+		if err != nil {
+			return err
+		}
+	}
 
 	opts := api.TaskListOptions{
-		Project:       c.Project,
+		Project:       project,
 		Assignee:      assignee,
-		Tag:           c.Tag,
+		Tag:           tag,
 		Due:           c.Due,
+		OverdueDays:   c.OverdueDays,
+		DueAfter:      dueAfter,
+		DueBefore:     dueBefore,
+		ModifiedAfter: modifiedAfter,
+		CreatedAfter:  createdAfter,
+		CompletedAfter: completedSince,
 		IncludeCompleted: c.All,
+		CompletedOnly: c.CompletedOnly,
+		IncludeSubtasks: c.IncludeSubtasks,
 		Limit:         c.Limit,
 		SortBy:        c.Sort,
+		SortDescending: c.Desc,
+		Unassigned:    c.Unassigned,
+		Assigned:      c.Assigned,
+	}
+	if c.Fields != "" || c.ShowAge || c.CompletedSince != "" || c.GroupBy == "section" {
+		opts.OptFields = taskColumns.optFields(fields)
+	}
+	if c.GroupBy == "section" {
+		opts.OptFields += ",memberships.project.gid,memberships.section.gid"
+	}
+	if c.PermalinkOnly && opts.OptFields != "" && !hasField(fields, "permalink") {
+		opts.OptFields += ",permalink_url"
 	}
 
-	tasks, err := client.ListTasks(opts)
+	if c.CountOnly {
+		return countTasks(client, opts)
+	}
+
+	// The API can't sort by name, so every matching task is fetched and
+	// sorted here, and only then cut to the limit
+	if c.Sort == "name" {
+		if c.JSONL {
+			return fmt.Errorf("--sort name needs every task first, so it can't be streamed with --jsonl")
+		}
+		opts.SortBy = ""
+		opts.Limit = 0
+	}
+
+	if c.JSONL {
+		return c.streamJSONL(c.withOptFields(client), opts)
+	}
+
+	tasks, err := c.withOptFields(client).ListTasks(opts)
 	if err != nil {
 		return err
 	}
+	if c.Sort == "name" {
+		sortTasksByName(tasks, c.Desc)
+		if c.Limit > 0 && len(tasks) > c.Limit {
+			tasks = tasks[:c.Limit]
+		}
+	}
+	if c.Sort == "due_date" {
+		moveUndated(tasks, c.NullsFirst)
+	}
 
+	if c.PermalinkOnly {
+		printPermalinks(tasks)
+		return nil
+	}
+	if c.JSONMeta {
+		return printSearchEnvelope(tasks, client.Workspace())
+	}
 	if c.JSON {
 		return printJSON(tasks)
 	}
@@ -70,83 +231,266 @@ func (c *TasksListCmd) Run(client *api.Client) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "GID\tNAME\tDUE\tASSIGNEE\tPROJECT")
-	fmt.Fprintln(w, "---\t----\t---\t--------\t-------")
+	var footer []string
+	if c.Limit > 0 && len(tasks) >= c.Limit {
+		footer = append(footer, fmt.Sprintf("(Showing %d tasks, use -l to increase limit)", c.Limit))
+	}
 
-	for _, task := range tasks {
-		assignee := "-"
-		if task.Assignee != nil {
-			assignee = task.Assignee.Name
+	if c.GroupBy == "section" {
+		sections, err := client.ListSections(project)
+		if err != nil {
+			return notFound(err, "project", project)
 		}
+		return printTaskGroups(g, groupBySection(project, sections, tasks), fields, footer)
+	}
 
-		due := "-"
-		if task.DueOn != "" {
-			due = task.DueOn
+	t := taskColumns.table(tasks, fields)
+	for _, f := range footer {
+		t.footerf("%s", f)
+	}
+	return t.print(g)
+}
+
+// groupBySection sorts tasks into the project's sections, in board order,
+// keeping the list order within each. Empty sections are left out, and
+// tasks in no section come last.
+func groupBySection(project string, sections []api.Section, tasks []api.Task) []boardColumn {
+	var groups []boardColumn
+	placed := map[string]bool{}
+	for _, col := range boardColumns(project, sections, tasks) {
+		if len(col.tasks) == 0 {
+			continue
+		}
+		groups = append(groups, col)
+		for _, t := range col.tasks {
+			placed[t.GID] = true
+		}
+	}
+
+	var rest []api.Task
+	for _, t := range tasks {
+		if !placed[t.GID] {
+			rest = append(rest, t)
 		}
+	}
+	if len(rest) > 0 {
+		groups = append(groups, boardColumn{name: "(No section)", tasks: rest})
+	}
+	return groups
+}
 
-		project := "-"
-		if len(task.Projects) > 0 {
-			project = task.Projects[0].Name
+// printTaskGroups prints a table of tasks under each group's name
+func printTaskGroups(g *Globals, groups []boardColumn, fields []string, footer []string) error {
+	var buf bytes.Buffer
+	for i, group := range groups {
+		if i > 0 {
+			buf.WriteString("\n")
 		}
+		fmt.Fprintf(&buf, "%s (%d)\n\n", group.name, len(group.tasks))
+		taskColumns.table(group.tasks, fields).render(&buf)
+	}
+	if len(footer) > 0 {
+		buf.WriteString("\n" + strings.Join(footer, "\n") + "\n")
+	}
+	return pageOutput(g, buf.Bytes())
+}
 
-		name := truncate(task.Name, 50)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", task.GID, name, due, assignee, project)
+// streamJSONL writes each page of tasks as it arrives, one compact JSON
+// object per line
+func (c *TasksListCmd) streamJSONL(client *api.Client, opts api.TaskListOptions) error {
+	enc := json.NewEncoder(dataOut)
+	opts.OnPage = func(tasks []api.Task) error {
+		for _, t := range tasks {
+			if err := enc.Encode(t); err != nil {
+				return fmt.Errorf("encoding JSON: %w", err)
+			}
+		}
+		return nil
 	}
 
-	w.Flush()
+	_, err := client.ListTasks(opts)
+	return err
+}
 
-	if len(tasks) >= c.Limit {
-		fmt.Printf("\n(Showing %d tasks, use -l to increase limit)\n", c.Limit)
+// printPermalinks prints each task's URL on its own line, for sharing
+func printPermalinks(tasks []api.Task) {
+	for _, t := range tasks {
+		if t.Permalink != "" {
+			fmt.Println(t.Permalink)
+		}
 	}
+}
 
+// countTasks prints how many tasks match opts. It walks every page but
+// fetches only GIDs and doesn't keep the tasks; a search still remembers
+// the GIDs it has seen to skip duplicates across pages.
+func countTasks(client *api.Client, opts api.TaskListOptions) error {
+	opts.OptFields = "gid"
+	opts.Limit = 0
+	opts.SortBy = ""
+
+	count := 0
+	opts.OnPage = func(tasks []api.Task) error {
+		count += len(tasks)
+		return nil
+	}
+	if _, err := client.ListTasks(opts); err != nil {
+		return err
+	}
+
+	fmt.Println(count)
 	return nil
 }
 
+// moveUndated moves the tasks without a due date to the end, or to the
+// start if first is true, keeping the order of the rest. The API's due
+// date sort puts them in no reliable place.
+func moveUndated(tasks []api.Task, first bool) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i].DueOn == "" && tasks[i].DueAt == "", tasks[j].DueOn == "" && tasks[j].DueAt == ""
+		if first {
+			return a && !b
+		}
+		return !a && b
+	})
+}
+
+// sortTasksByName sorts tasks alphabetically, ignoring case
+func sortTasksByName(tasks []api.Task, desc bool) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := strings.ToLower(tasks[i].Name), strings.ToLower(tasks[j].Name)
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
+
 type TasksGetCmd struct {
-	TaskGID  string `arg:"" help:"Task GID to retrieve"`
+	TaskGID  string `arg:"" optional:"" help:"Task GID to retrieve"`
 	Comments bool   `help:"Include comments and activity"`
-	JSON     bool   `short:"j" help:"Output as JSON"`
+	CommentsOnly bool `help:"Include comments but not system activity"`
+	Subtasks bool   `help:"List the task's subtasks"`
+	Web      bool   `help:"Open the task in the browser instead of printing it (no -w, which is --workspace)" xor:"diff"`
+	Diff     string `placeholder:"OTHER-GID" help:"Compare the task field by field with another task, e.g. a suspected duplicate" xor:"diff"`
+	JSON     bool   `short:"j" help:"Output as JSON" xor:"json"`
+	Raw      bool   `help:"Print the API's JSON response unchanged, including fields the CLI doesn't model" xor:"json,diff"`
+	HTML     bool   `help:"Write a self-contained HTML report of the task, with comments (save it with --out)" xor:"json,diff"`
+	OptFieldsFlags
 }
 
-func (c *TasksGetCmd) Run(client *api.Client) error {
-	task, err := client.GetTask(c.TaskGID)
-	if err != nil {
+func (c *TasksGetCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
 		return err
 	}
 
-	// Fetch comments if requested
-	var stories []api.Story
-	if c.Comments {
-		stories, err = client.GetTaskStories(c.TaskGID)
+	if c.Diff != "" {
+		return printTaskDiff(client, g, c.TaskGID, c.Diff, c.JSON)
+	}
+
+	if c.Raw {
+		body, err := c.withOptFields(client).GetTaskRaw(c.TaskGID, "")
 		if err != nil {
-			return err
+			return notFound(err, "task", c.TaskGID)
+		}
+		if !bytes.HasSuffix(body, []byte("\n")) {
+			body = append(body, '\n')
 		}
+		_, err = dataOut.Write(body)
+		return err
 	}
 
-	// Fetch attachments
-	attachments, err := client.ListAttachments(c.TaskGID)
-	if err != nil {
-		return err
+	if c.Web {
+		task, err := client.GetTask(c.TaskGID)
+		if err != nil {
+			return notFound(err, "task", c.TaskGID)
+		}
+		url := task.Permalink
+		if url == "" {
+			workspace := client.Workspace()
+			if workspace == "" {
+				workspace = "0"
+			}
+			url = fmt.Sprintf("https://app.asana.com/0/%s/%s", workspace, task.GID)
+		}
+		return openBrowser(url)
+	}
+
+	// A report includes the discussion unless asked for comments only
+	if c.HTML && !c.CommentsOnly {
+		c.Comments = true
+	}
+
+	// Fetch the task, its attachments and (if requested) comments concurrently
+	var (
+		task        *api.Task
+		stories     []api.Story
+		attachments []api.Attachment
+		subtasks    []api.Task
+	)
+	fetches := []func(ctx context.Context) error{
+		func(ctx context.Context) (err error) {
+			task, err = c.withOptFields(client).WithContext(ctx).GetTask(c.TaskGID)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			attachments, err = client.WithContext(ctx).ListAttachments(c.TaskGID)
+			return err
+		},
+	}
+	if c.Comments || c.CommentsOnly {
+		fetches = append(fetches, func(ctx context.Context) (err error) {
+			stories, err = client.WithContext(ctx).GetTaskStories(c.TaskGID, c.CommentsOnly)
+			return err
+		})
+	}
+	if c.Subtasks {
+		fetches = append(fetches, func(ctx context.Context) (err error) {
+			subtasks, err = client.WithContext(ctx).ListSubtasks(c.TaskGID)
+			return err
+		})
+	}
+	if err := runParallel(fetches...); err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	// The progress line needs the subtasks' completion states
+	if !c.Subtasks && task.NumSubtasks > 0 && !c.JSON {
+		var err error
+		if subtasks, err = client.ListSubtasks(c.TaskGID); err != nil {
+			return err
+		}
 	}
 
 	if c.JSON {
-		if c.Comments {
-			return printJSON(map[string]interface{}{
-				"task":        task,
-				"comments":    stories,
-				"attachments": attachments,
-			})
-		}
-		return printJSON(map[string]interface{}{
+		out := map[string]interface{}{
 			"task":        task,
 			"attachments": attachments,
-		})
+		}
+		if c.Comments || c.CommentsOnly {
+			out["comments"] = stories
+		}
+		if c.Subtasks {
+			out["subtasks"] = subtasks
+		}
+		return printJSON(out)
+	}
+	if c.HTML {
+		return writeTaskHTML(dataOut, task, subtasks, attachments, stories)
 	}
 
 	fmt.Printf("Task: %s\n", task.Name)
 	fmt.Printf("GID: %s\n", task.GID)
 	fmt.Printf("Status: %s\n", statusString(task.Completed))
+	if len(subtasks) > 0 {
+		done := 0
+		for _, st := range subtasks {
+			if st.Completed {
+				done++
+			}
+		}
+		fmt.Printf("Subtasks: %d/%d complete\n", done, len(subtasks))
+	}
 
 	if task.Assignee != nil {
 		fmt.Printf("Assignee: %s", task.Assignee.Name)
@@ -156,6 +500,22 @@ func (c *TasksGetCmd) Run(client *api.Client) error {
 		fmt.Println()
 	}
 
+	if task.NumLikes > 0 {
+		likes := fmt.Sprintf("%d", task.NumLikes)
+		if task.Liked {
+			likes += " (including you)"
+		}
+		fmt.Printf("Likes: %s\n", likes)
+	}
+
+	if task.ActualTimeMinutes > 0 {
+		fmt.Printf("Time tracked: %s\n", formatMinutes(task.ActualTimeMinutes))
+	}
+
+	if task.StartOn != "" {
+		fmt.Printf("Start: %s\n", task.StartOn)
+	}
+
 	if task.DueOn != "" {
 		fmt.Printf("Due: %s\n", task.DueOn)
 	}
@@ -176,8 +536,8 @@ func (c *TasksGetCmd) Run(client *api.Client) error {
 		fmt.Printf("Tags: %s\n", strings.Join(tags, ", "))
 	}
 
-	fmt.Printf("Created: %s\n", task.CreatedAt)
-	fmt.Printf("Modified: %s\n", task.ModifiedAt)
+	fmt.Printf("Created: %s\n", formatTime(task.CreatedAt))
+	fmt.Printf("Modified: %s\n", formatTime(task.ModifiedAt))
 
 	if task.Permalink != "" {
 		fmt.Printf("URL: %s\n", task.Permalink)
@@ -187,6 +547,17 @@ func (c *TasksGetCmd) Run(client *api.Client) error {
 		fmt.Printf("\nDescription:\n%s\n", task.Notes)
 	}
 
+	if c.Subtasks && len(subtasks) > 0 {
+		fmt.Printf("\nSubtasks (%d):\n", len(subtasks))
+		for _, st := range subtasks {
+			check := " "
+			if st.Completed {
+				check = "x"
+			}
+			fmt.Printf("  [%s] %s [%s]\n", check, st.Name, st.GID)
+		}
+	}
+
 	// Display attachments
 	if len(attachments) > 0 {
 		fmt.Printf("\nAttachments (%d):\n", len(attachments))
@@ -200,23 +571,15 @@ func (c *TasksGetCmd) Run(client *api.Client) error {
 	}
 
 	// Display comments/activity
-	if c.Comments && len(stories) > 0 {
-		fmt.Printf("\nComments & Activity (%d):\n", len(stories))
+	if len(stories) > 0 {
+		heading := "Comments & Activity"
+		if c.CommentsOnly {
+			heading = "Comments"
+		}
+		fmt.Printf("\n%s (%d):\n", heading, len(stories))
 		fmt.Println(strings.Repeat("-", 40))
 		for _, story := range stories {
-			author := "Unknown"
-			if story.CreatedBy != nil {
-				author = story.CreatedBy.Name
-			}
-			timestamp := story.CreatedAt
-			if len(timestamp) > 10 {
-				timestamp = timestamp[:10]
-			}
-			fmt.Printf("[%s] %s\n", timestamp, author)
-			if story.Text != "" {
-				fmt.Printf("  %s\n", story.Text)
-			}
-			fmt.Println()
+			printStory(story)
 		}
 	}
 
@@ -225,47 +588,148 @@ func (c *TasksGetCmd) Run(client *api.Client) error {
 
 type TasksCommentCmd struct {
 	TaskGID string `arg:"" help:"Task GID to comment on"`
-	Message string `arg:"" help:"Comment message (use --html for rich text)"`
+	Message string `arg:"" optional:"" help:"Comment message (use --html for rich text)"`
+	File    string `short:"F" help:"Read the comment from a file ('-' for stdin); .html files are sent as rich text"`
 	HTML    bool   `help:"Treat message as HTML rich text"`
+	Pin     bool   `aliases:"sticky" help:"Pin the comment to the top of the task"`
+	Mention []string `sep:"none" help:"User name, email, GID or 'me' to @-mention (repeatable); replaces @<user> in the message or is added at the end"`
 }
 
-func (c *TasksCommentCmd) Run(client *api.Client) error {
+func (c *TasksCommentCmd) Run(client *api.Client, g *Globals) error {
 	message := c.Message
+	isHTML := c.HTML
+
+	switch {
+	case c.File != "" && c.Message != "":
+		return fmt.Errorf("give either a message or --file, not both")
+	case c.File == "-":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		message = string(data)
+	case c.File != "":
+		data, err := os.ReadFile(c.File)
+		if err != nil {
+			return fmt.Errorf("reading comment file: %w", err)
+		}
+		message = string(data)
+		ext := strings.ToLower(filepath.Ext(c.File))
+		if ext == ".html" || ext == ".htm" {
+			isHTML = true
+		}
+	}
+
+	message = strings.TrimRight(message, "\n")
+	if strings.TrimSpace(message) == "" && len(c.Mention) == 0 {
+		return fmt.Errorf("comment is empty")
+	}
+
+	// Mentions only exist in rich text, so they switch the comment to HTML
+	if len(c.Mention) > 0 {
+		gids := make([]string, len(c.Mention))
+		for i, ref := range c.Mention {
+			gid, err := resolveUser(client, ref)
+			if err != nil {
+				return fmt.Errorf("--mention: %w", err)
+			}
+			gids[i] = gid
+		}
+		message = addMentions(message, isHTML, c.Mention, gids)
+		isHTML = true
+	}
 
 	// If HTML flag is set but message doesn't have body tags, wrap it
-	if c.HTML && !strings.Contains(message, "<body>") {
+	if isHTML && !strings.Contains(message, "<body>") {
 		message = "<body>" + message + "</body>"
 	}
 
-	story, err := client.AddComment(c.TaskGID, message, c.HTML)
+	story, err := client.AddComment(c.TaskGID, message, isHTML)
 	if err != nil {
-		return err
+		return notFound(err, "task", c.TaskGID)
+	}
+	if c.Pin {
+		if _, err := client.SetStoryPinned(story.GID, true); err != nil {
+			return fmt.Errorf("comment %s added, but pinning it failed: %w", story.GID, err)
+		}
+	}
+
+	if g.Quiet {
+		fmt.Println(story.GID)
+		return nil
 	}
 
 	fmt.Printf("Comment added successfully (ID: %s)\n", story.GID)
-	fmt.Printf("Created at: %s\n", story.CreatedAt)
+	fmt.Printf("Created at: %s\n", formatTime(story.CreatedAt))
 
 	return nil
 }
 
+// addMentions returns message as rich text that @-mentions each user:
+// "@ref" in the message is replaced by the mention of the user ref
+// resolved to, and users it doesn't name are mentioned at the end
+func addMentions(message string, isHTML bool, refs, gids []string) string {
+	if !isHTML {
+		message = html.EscapeString(message)
+	}
+	message = strings.TrimSuffix(strings.TrimPrefix(message, "<body>"), "</body>")
+
+	var trailing []string
+	for i, ref := range refs {
+		mention := fmt.Sprintf(`<a data-asana-gid="%s"/>`, gids[i])
+		var found bool
+		message, found = replaceMention(message, "@"+html.EscapeString(ref), mention)
+		if !found {
+			trailing = append(trailing, mention)
+		}
+	}
+	if len(trailing) > 0 {
+		if message != "" {
+			message += " "
+		}
+		message += strings.Join(trailing, " ")
+	}
+
+	return "<body>" + message + "</body>"
+}
+
+// replaceMention replaces each at in message with mention, unless a word
+// character follows it, so that "@al" doesn't match the start of "@alice".
+// It reports whether anything was replaced.
+func replaceMention(message, at, mention string) (string, bool) {
+	var b strings.Builder
+	found := false
+	for {
+		i := strings.Index(message, at)
+		if i < 0 {
+			break
+		}
+		end := i + len(at)
+		b.WriteString(message[:i])
+		if r, _ := utf8.DecodeRuneInString(message[end:]); end < len(message) && (r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteString(at)
+		} else {
+			b.WriteString(mention)
+			found = true
+		}
+		message = message[end:]
+	}
+	b.WriteString(message)
+	return b.String(), found
+}
+
 type TasksUncommentCmd struct {
 	StoryGID string `arg:"" help:"Comment/story GID to delete"`
-	Force    bool   `short:"f" help:"Skip confirmation"`
+	Force    bool   `short:"f" help:"Skip confirmation (same as --yes)"`
 }
 
-func (c *TasksUncommentCmd) Run(client *api.Client) error {
-	if !c.Force {
-		fmt.Printf("Are you sure you want to delete comment %s? [y/N] ", c.StoryGID)
-		var response string
-		fmt.Scanln(&response)
-		if response != "y" && response != "Y" {
-			fmt.Println("Cancelled.")
-			return nil
-		}
+func (c *TasksUncommentCmd) Run(client *api.Client, g *Globals) error {
+	if !c.Force && !g.confirm(fmt.Sprintf("Are you sure you want to delete comment %s?", c.StoryGID)) {
+		return nil
 	}
 
 	if err := client.DeleteStory(c.StoryGID); err != nil {
-		return err
+		return notFound(err, "comment", c.StoryGID)
 	}
 
 	fmt.Printf("Comment %s deleted.\n", c.StoryGID)
@@ -273,57 +737,100 @@ func (c *TasksUncommentCmd) Run(client *api.Client) error {
 }
 
 type TasksSearchCmd struct {
-	Query string `arg:"" help:"Search query"`
-	Limit int    `short:"l" default:"100" help:"Maximum number of tasks to return"`
-	JSON  bool   `short:"j" help:"Output as JSON"`
+	Query  string `arg:"" help:"Search query"`
+
+	// Filter flags, as for tasks list
+	Mine          bool   `short:"m" help:"Search only tasks assigned to me (shortcut for -a me)" xor:"assignee"`
+	Project       string `short:"p" help:"Search only a project (GID or name)"`
+	Assignee      string `short:"a" help:"Search only tasks assigned to a user (GID, email, name or 'me')" xor:"assignee"`
+	Tag           string `short:"t" help:"Search only tasks with a tag (GID)" xor:"tag"`
+	TagName       string `help:"Search only tasks with a tag (name)" xor:"tag"`
+	Due           string `short:"d" help:"Filter by due date: today, tomorrow, week, overdue, or YYYY-MM-DD"`
+	All           bool   `help:"Include completed tasks" xor:"completed"`
+	CompletedOnly bool   `help:"Search only completed tasks" xor:"completed"`
+
+	Limit  int    `short:"l" default:"100" help:"Maximum number of tasks to return (0 for no limit)"`
+	Fields   string `help:"Comma-separated columns to show (gid,name,due,assignee,project,projects,tags,completed,created,modified,permalink)"`
+	JSON     bool   `short:"j" help:"Output as JSON" xor:"output"`
+	JSONMeta bool   `help:"Output as JSON wrapped with count and workspace" xor:"output"`
+	PermalinkOnly bool `help:"Print only the tasks' URLs, one per line" xor:"output"`
+	CountOnly bool  `help:"Print only the number of matching tasks, counting every page (--limit is ignored)" xor:"output"`
+	OptFieldsFlags
 }
 
-func (c *TasksSearchCmd) Run(client *api.Client) error {
-	tasks, err := client.SearchTasks(c.Query, c.Limit)
+func (c *TasksSearchCmd) Run(client *api.Client, g *Globals) error {
+	fields, err := taskColumns.parse(c.Fields)
 	if err != nil {
 		return err
 	}
 
-	if c.JSON {
-		return printJSON(tasks)
+	assignee := c.Assignee
+	if c.Mine {
+		assignee = "me"
 	}
-
-	if len(tasks) == 0 {
-		fmt.Println("No tasks found.")
-		return nil
+	if assignee, err = resolveUser(client, assignee); err != nil {
+		return err
 	}
-
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "GID\tNAME\tDUE\tASSIGNEE\tPROJECT")
-	fmt.Fprintln(w, "---\t----\t---\t--------\t-------")
-
-	for _, task := range tasks {
-		assignee := "-"
-		if task.Assignee != nil {
-			assignee = task.Assignee.Name
+	project, err := resolveProject(client, c.Project)
+	if err != nil {
+		return err
+	}
+	tag := c.Tag
+	if c.TagName != "" {
+		if tag, err = resolveTag(client, c.TagName); err != nil {
+			return err
 		}
+	}
 
-		due := "-"
-		if task.DueOn != "" {
-			due = task.DueOn
+	// Subtasks are found too, marked with their parent
+	opts := api.TaskListOptions{
+		Project:          project,
+		Assignee:         assignee,
+		Tag:              tag,
+		Due:              c.Due,
+		IncludeCompleted: c.All,
+		CompletedOnly:    c.CompletedOnly,
+		IncludeSubtasks:  true,
+		Limit:            c.Limit,
+	}
+	if c.Fields != "" {
+		opts.OptFields = taskColumns.optFields(fields)
+		if c.PermalinkOnly && !hasField(fields, "permalink") {
+			opts.OptFields += ",permalink_url"
 		}
+	}
 
-		project := "-"
-		if len(task.Projects) > 0 {
-			project = task.Projects[0].Name
-		}
+	if c.CountOnly {
+		opts.Text = c.Query
+		return countTasks(client, opts)
+	}
 
-		name := truncate(task.Name, 50)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", task.GID, name, due, assignee, project)
+	tasks, err := c.withOptFields(client).SearchTasks(c.Query, opts)
+	if err != nil {
+		return err
 	}
 
-	w.Flush()
+	if c.PermalinkOnly {
+		printPermalinks(tasks)
+		return nil
+	}
+	if c.JSONMeta {
+		return printSearchEnvelope(tasks, client.Workspace())
+	}
+	if c.JSON {
+		return printJSON(tasks)
+	}
 
-	if len(tasks) >= c.Limit {
-		fmt.Printf("\n(Showing %d tasks, use -l to increase limit)\n", c.Limit)
+	if len(tasks) == 0 {
+		fmt.Println("No tasks found.")
+		return nil
 	}
 
-	return nil
+	t := taskColumns.table(tasks, fields)
+	if c.Limit > 0 && len(tasks) >= c.Limit {
+		t.footerf("(Showing %d tasks, use -l to increase limit)", c.Limit)
+	}
+	return t.print(g)
 }
 
 func statusString(completed bool) string {
@@ -333,29 +840,54 @@ func statusString(completed bool) string {
 	return "Open"
 }
 
-func truncate(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
-	}
-	return s[:maxLen-3] + "..."
-}
-
 // TasksCreateCmd creates a new task
 type TasksCreateCmd struct {
 	Name     string   `arg:"" help:"Task name"`
 	Notes    string   `short:"n" help:"Task description (plain text, or HTML with --html)"`
 	HTML     bool     `help:"Treat notes as HTML rich text"`
-	Assignee string   `short:"a" help:"Assignee GID or 'me'"`
-	Due      string   `short:"d" help:"Due date (YYYY-MM-DD)"`
-	Project  string   `short:"p" help:"Project GID to add task to"`
+	Assignee string   `short:"a" help:"Assignee GID, email, name or 'me'"`
+	Due      string   `short:"d" help:"Due date: YYYY-MM-DD, today, tomorrow, a weekday, or +3d/+2w"`
+	Start    string   `help:"Start date, in the same formats as --due (needs --due)"`
+	Project  []string `short:"p" sep:"none" help:"Project GID or name to add the task to (repeatable)"`
+	Section  string   `help:"Section GID to place the task in (needs exactly one --project)"`
 	JSON     bool     `short:"j" help:"Output as JSON"`
 }
 
-func (c *TasksCreateCmd) Run(client *api.Client) error {
+func (c *TasksCreateCmd) Run(client *api.Client, g *Globals, cfg *config.Config) error {
+	defaultProject, defaultAssignee := g.defaults(cfg)
+	if len(c.Project) == 0 && defaultProject != "" {
+		c.Project = []string{defaultProject}
+	}
+	if c.Assignee == "" {
+		c.Assignee = defaultAssignee
+	}
+
+	if c.Section != "" && len(c.Project) != 1 {
+		return fmt.Errorf("--section needs exactly one --project")
+	}
+
+	due, err := parseDueDate(c.Due, time.Now())
+	if err != nil {
+		return err
+	}
+	start, err := parseDueDate(c.Start, time.Now())
+	if err != nil {
+		return fmt.Errorf("--start: %w", err)
+	}
+	if err := checkStartDate(start, due); err != nil {
+		return err
+	}
+
+	assignee, err := resolveUser(client, c.Assignee)
+	if err != nil {
+		return err
+	}
+
 	opts := api.CreateTaskOptions{
 		Name:     c.Name,
-		Assignee: c.Assignee,
-		DueOn:    c.Due,
+		Assignee: assignee,
+		StartOn:  start,
+		DueOn:    due,
 	}
 
 	if c.HTML && c.Notes != "" {
@@ -368,8 +900,12 @@ func (c *TasksCreateCmd) Run(client *api.Client) error {
 		opts.Notes = c.Notes
 	}
 
-	if c.Project != "" {
-		opts.Projects = []string{c.Project}
+	for _, ref := range c.Project {
+		project, err := resolveProject(client, ref)
+		if err != nil {
+			return err
+		}
+		opts.Projects = append(opts.Projects, project)
 	}
 
 	task, err := client.CreateTask(opts)
@@ -377,9 +913,20 @@ func (c *TasksCreateCmd) Run(client *api.Client) error {
 		return err
 	}
 
+	// The create endpoint can't set a section, so move the task there after
+	if c.Section != "" {
+		if err := client.AddTaskToProject(task.GID, opts.Projects[0], c.Section); err != nil {
+			return fmt.Errorf("task %s was created but could not be moved to section %s: %w", task.GID, c.Section, err)
+		}
+	}
+
 	if c.JSON {
 		return printJSON(task)
 	}
+	if g.Quiet {
+		fmt.Println(task.GID)
+		return nil
+	}
 
 	fmt.Printf("Task created successfully!\n")
 	fmt.Printf("GID: %s\n", task.GID)
@@ -393,28 +940,102 @@ func (c *TasksCreateCmd) Run(client *api.Client) error {
 
 // TasksCompleteCmd marks a task as complete
 type TasksCompleteCmd struct {
-	TaskGID string `arg:"" help:"Task GID to complete"`
+	TaskGID          string `arg:"" optional:"" help:"Task GID to complete"`
+	Force            bool   `short:"f" help:"Complete without confirmation even if subtasks are still open"`
+	CompleteSubtasks bool   `help:"Complete all open subtasks first"`
+	ConfirmFlags
 }
 
-func (c *TasksCompleteCmd) Run(client *api.Client) error {
+func (c *TasksCompleteCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
+	subtasks, err := client.ListSubtasks(c.TaskGID)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	var open []api.Task
+	for _, st := range subtasks {
+		if !st.Completed {
+			open = append(open, st)
+		}
+	}
+
+	force := c.Force
+	if c.Confirm {
+		current, err := client.GetTask(c.TaskGID)
+		if err != nil {
+			return notFound(err, "task", c.TaskGID)
+		}
+		changes := []fieldChange{{"status", statusString(current.Completed), statusString(true)}}
+		if len(open) > 0 {
+			after := fmt.Sprintf("%d (left open)", len(open))
+			if c.CompleteSubtasks {
+				after = "0"
+			}
+			changes = append(changes, fieldChange{"open subtasks", fmt.Sprint(len(open)), after})
+		}
+		if !c.confirmChanges(g, current, changes) {
+			return nil
+		}
+		force = true // the preview already showed the open subtasks
+	}
+
+	if len(open) > 0 {
+		if c.CompleteSubtasks {
+			for _, st := range open {
+				if _, err := client.CompleteTask(st.GID); err != nil {
+					return fmt.Errorf("completing subtask %s: %w", st.GID, err)
+				}
+				if !g.Quiet {
+					fmt.Printf("Subtask completed: %s\n", st.Name)
+				}
+			}
+		} else if !force {
+			fmt.Printf("This task has %d incomplete subtask(s):\n", len(open))
+			for _, st := range open {
+				fmt.Printf("  - %s [%s]\n", st.Name, st.GID)
+			}
+			if !g.confirm("Complete it anyway?") {
+				return nil
+			}
+		}
+	}
+
 	task, err := client.CompleteTask(c.TaskGID)
 	if err != nil {
 		return err
 	}
 
+	if g.Quiet {
+		fmt.Println(task.GID)
+		return nil
+	}
+
 	fmt.Printf("Task completed: %s\n", task.Name)
 	return nil
 }
 
 // TasksReopenCmd reopens a completed task
 type TasksReopenCmd struct {
-	TaskGID string `arg:"" help:"Task GID to reopen"`
+	TaskGID string `arg:"" optional:"" help:"Task GID to reopen"`
 }
 
-func (c *TasksReopenCmd) Run(client *api.Client) error {
+func (c *TasksReopenCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
 	task, err := client.ReopenTask(c.TaskGID)
 	if err != nil {
-		return err
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if g.Quiet {
+		fmt.Println(task.GID)
+		return nil
 	}
 
 	fmt.Printf("Task reopened: %s\n", task.Name)
@@ -423,23 +1044,44 @@ func (c *TasksReopenCmd) Run(client *api.Client) error {
 
 // TasksUpdateCmd updates an existing task
 type TasksUpdateCmd struct {
-	TaskGID  string `arg:"" help:"Task GID to update"`
+	TaskGID  string `arg:"" optional:"" help:"Task GID to update"`
 	Name     string `short:"n" help:"New task name"`
-	Notes    string `help:"New task description (plain text, or HTML with --html)"`
+	Notes    string `help:"New task description (plain text, or HTML with --html); pass \"\" to clear"`
 	HTML     bool   `help:"Treat notes as HTML rich text"`
-	Assignee string `short:"a" help:"New assignee GID or 'me'"`
-	Due      string `short:"d" help:"New due date (YYYY-MM-DD)"`
+	Assignee string `short:"a" help:"New assignee GID, email, name or 'me'; pass \"\" to unassign"`
+	Due      string `short:"d" help:"New due date: YYYY-MM-DD, today, tomorrow, a weekday, or +3d/+2w; pass \"\" to clear" xor:"due"`
+	ClearDue bool   `help:"Remove the due date" xor:"due"`
+	Start    string `help:"New start date, in the same formats as --due; pass \"\" to clear"`
 	JSON     bool   `short:"j" help:"Output as JSON"`
+	ConfirmFlags
 }
 
-func (c *TasksUpdateCmd) Run(client *api.Client) error {
+func (c *TasksUpdateCmd) Run(client *api.Client, g *Globals, ctx *kong.Context) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
 	opts := api.UpdateTaskOptions{}
 
-	if c.Name != "" {
+	// The current task is needed for --start and --confirm; fetch it once
+	var current *api.Task
+	getCurrent := func() (*api.Task, error) {
+		if current == nil {
+			task, err := client.GetTask(c.TaskGID)
+			if err != nil {
+				return nil, notFound(err, "task", c.TaskGID)
+			}
+			current = task
+		}
+		return current, nil
+	}
+
+	// Only send fields whose flags were given, so empty values can clear them
+	if flagProvided(ctx, "name") {
 		opts.Name = &c.Name
 	}
-	if c.Notes != "" {
-		if c.HTML {
+	if flagProvided(ctx, "notes") {
+		if c.HTML && c.Notes != "" {
 			notes := c.Notes
 			if !strings.Contains(notes, "<body>") {
 				notes = "<body>" + notes + "</body>"
@@ -449,16 +1091,65 @@ func (c *TasksUpdateCmd) Run(client *api.Client) error {
 			opts.Notes = &c.Notes
 		}
 	}
-	if c.Assignee != "" {
-		opts.Assignee = &c.Assignee
+	if flagProvided(ctx, "assignee") {
+		assignee, err := resolveUser(client, c.Assignee)
+		if err != nil {
+			return err
+		}
+		opts.Assignee = &assignee
+	}
+	if flagProvided(ctx, "due") {
+		due, err := parseDueDate(c.Due, time.Now())
+		if err != nil {
+			return err
+		}
+		opts.DueOn = &due
+	}
+	if c.ClearDue {
+		none := ""
+		opts.DueOn = &none
 	}
-	if c.Due != "" {
-		opts.DueOn = &c.Due
+	if flagProvided(ctx, "start") {
+		start, err := parseDueDate(c.Start, time.Now())
+		if err != nil {
+			return fmt.Errorf("--start: %w", err)
+		}
+		opts.StartOn = &start
+
+		// The API wants the due date in the same request as the start date
+		due := ""
+		if opts.DueOn != nil {
+			due = *opts.DueOn
+		} else {
+			task, err := getCurrent()
+			if err != nil {
+				return err
+			}
+			keepCurrentDue(&opts, task)
+			due = task.DueOn
+		}
+		if err := checkStartDate(start, due); err != nil {
+			return err
+		}
+	}
+
+	if opts == (api.UpdateTaskOptions{}) {
+		return fmt.Errorf("nothing to update: pass at least one of --name, --notes, --assignee, --due, --clear-due or --start")
+	}
+
+	if c.Confirm {
+		task, err := getCurrent()
+		if err != nil {
+			return err
+		}
+		if !c.confirmChanges(g, task, updateChanges(task, opts, c.Assignee)) {
+			return nil
+		}
 	}
 
 	task, err := client.UpdateTask(c.TaskGID, opts)
 	if err != nil {
-		return err
+		return notFound(err, "task", c.TaskGID)
 	}
 
 	if c.JSON {
@@ -469,28 +1160,385 @@ func (c *TasksUpdateCmd) Run(client *api.Client) error {
 	return nil
 }
 
-// TasksDeleteCmd deletes a task
-type TasksDeleteCmd struct {
-	TaskGID string `arg:"" help:"Task GID to delete"`
-	Force   bool   `short:"f" help:"Skip confirmation"`
+// updateChanges lists the fields an update changes, for --confirm. ref is
+// the assignee as given on the command line, shown instead of a bare GID.
+func updateChanges(task *api.Task, opts api.UpdateTaskOptions, ref string) []fieldChange {
+	var changes []fieldChange
+	if opts.Name != nil {
+		changes = append(changes, fieldChange{"name", previewValue(task.Name), previewValue(*opts.Name)})
+	}
+	if opts.HTMLNotes != nil {
+		changes = append(changes, fieldChange{"notes", previewValue(task.HTMLNotes), previewValue(*opts.HTMLNotes)})
+	} else if opts.Notes != nil {
+		changes = append(changes, fieldChange{"notes", previewValue(task.Notes), previewValue(*opts.Notes)})
+	}
+	if opts.Assignee != nil {
+		changes = append(changes, assigneeChange(task, *opts.Assignee, ref))
+	}
+	if opts.DueOn != nil {
+		changes = append(changes, fieldChange{"due", previewValue(task.DueOn), previewValue(*opts.DueOn)})
+	}
+	if opts.StartOn != nil {
+		changes = append(changes, fieldChange{"start", previewValue(task.StartOn), previewValue(*opts.StartOn)})
+	}
+	return changes
+}
+
+// assigneeChange describes assigning task to the user with the given GID
+// ("" to unassign), who was given as ref on the command line
+func assigneeChange(task *api.Task, gid, ref string) fieldChange {
+	before := assigneeName(task)
+	after := previewValue(ref)
+	if gid == "" {
+		after = "-"
+	} else if task.Assignee != nil && task.Assignee.GID == gid {
+		after = before
+	}
+	return fieldChange{"assignee", before, after}
+}
+
+// TasksAssignCmd assigns a task to a user
+type TasksAssignCmd struct {
+	TaskGID  string `arg:"" optional:"" help:"Task GID to assign"`
+	Assignee string `arg:"" optional:"" help:"Assignee GID, email, name or 'me'"`
+	JSON     bool   `short:"j" help:"Output as JSON"`
+	ConfirmFlags
 }
 
-func (c *TasksDeleteCmd) Run(client *api.Client) error {
-	if !c.Force {
-		fmt.Printf("Are you sure you want to delete task %s? [y/N] ", c.TaskGID)
-		var response string
-		fmt.Scanln(&response)
-		if response != "y" && response != "Y" {
-			fmt.Println("Cancelled.")
+func (c *TasksAssignCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+	if err := pickUser(client, g, &c.Assignee); err != nil {
+		return err
+	}
+
+	assignee, err := resolveUser(client, c.Assignee)
+	if err != nil {
+		return err
+	}
+
+	if c.Confirm {
+		current, err := client.GetTask(c.TaskGID)
+		if err != nil {
+			return notFound(err, "task", c.TaskGID)
+		}
+		if !c.confirmChanges(g, current, []fieldChange{assigneeChange(current, assignee, c.Assignee)}) {
 			return nil
 		}
 	}
 
-	err := client.DeleteTask(c.TaskGID)
+	task, err := client.AssignTask(c.TaskGID, assignee)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if c.JSON {
+		return printJSON(task)
+	}
+
+	name := assignee
+	if task.Assignee != nil && task.Assignee.Name != "" {
+		name = task.Assignee.Name
+	}
+	fmt.Printf("Task assigned: %s -> %s\n", task.Name, name)
+	return nil
+}
+
+// TasksUnassignCmd removes the assignee from a task
+type TasksUnassignCmd struct {
+	TaskGID string `arg:"" optional:"" help:"Task GID to unassign"`
+	JSON    bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *TasksUnassignCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
+	task, err := client.UnassignTask(c.TaskGID)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if c.JSON {
+		return printJSON(task)
+	}
+
+	fmt.Printf("Task unassigned: %s\n", task.Name)
+	return nil
+}
+
+// TasksLikeCmd likes a task
+type TasksLikeCmd struct {
+	TaskGID string `arg:"" optional:"" help:"Task GID to like"`
+	JSON    bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *TasksLikeCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
+	task, err := client.LikeTask(c.TaskGID)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if c.JSON {
+		return printJSON(task)
+	}
+
+	fmt.Printf("Task liked: %s\n", task.Name)
+	return nil
+}
+
+// TasksUnlikeCmd removes the current user's like from a task
+type TasksUnlikeCmd struct {
+	TaskGID string `arg:"" optional:"" help:"Task GID to unlike"`
+	JSON    bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *TasksUnlikeCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
+	task, err := client.UnlikeTask(c.TaskGID)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if c.JSON {
+		return printJSON(task)
+	}
+
+	fmt.Printf("Task unliked: %s\n", task.Name)
+	return nil
+}
+
+// TasksReorderCmd moves a task to a position in a section
+type TasksReorderCmd struct {
+	TaskGID string `arg:"" help:"Task GID to move"`
+	Section string `required:"" help:"Section GID to move the task into"`
+	Before  string `help:"Place the task just before this task GID" xor:"position"`
+	After   string `help:"Place the task just after this task GID" xor:"position"`
+}
+
+func (c *TasksReorderCmd) Run(client *api.Client) error {
+	ref := c.Before + c.After
+	if ref == c.TaskGID {
+		return fmt.Errorf("a task can't be placed relative to itself")
+	}
+
+	// Check that the move makes sense before asking the API to do it
+	var section *api.Section
+	var taskMemberships, refMemberships []api.Membership
+	fns := []func(ctx context.Context) error{
+		func(ctx context.Context) error {
+			var err error
+			section, err = client.WithContext(ctx).GetSection(c.Section)
+			return notFound(err, "section", c.Section)
+		},
+		func(ctx context.Context) error {
+			var err error
+			taskMemberships, err = client.WithContext(ctx).GetTaskMemberships(c.TaskGID)
+			return notFound(err, "task", c.TaskGID)
+		},
+	}
+	if ref != "" {
+		fns = append(fns, func(ctx context.Context) error {
+			var err error
+			refMemberships, err = client.WithContext(ctx).GetTaskMemberships(ref)
+			return notFound(err, "task", ref)
+		})
+	}
+	if err := runParallel(fns...); err != nil {
+		return err
+	}
+
+	if section.Project == nil {
+		return fmt.Errorf("section %s does not belong to a project", c.Section)
+	}
+	if _, ok := sectionIn(taskMemberships, section.Project.GID); !ok {
+		return fmt.Errorf("task %s is not in project %q, which section %q belongs to", c.TaskGID, section.Project.Name, section.Name)
+	}
+	if ref != "" {
+		if s, _ := sectionIn(refMemberships, section.Project.GID); s != section.GID {
+			return fmt.Errorf("task %s is not in section %q; --before and --after must name a task in the target section", ref, section.Name)
+		}
+	}
+
+	if err := client.AddTaskToSection(section.GID, c.TaskGID, c.Before, c.After); err != nil {
+		return err
+	}
+
+	switch {
+	case c.Before != "":
+		fmt.Printf("Task %s moved to %q, before %s\n", c.TaskGID, section.Name, c.Before)
+	case c.After != "":
+		fmt.Printf("Task %s moved to %q, after %s\n", c.TaskGID, section.Name, c.After)
+	default:
+		fmt.Printf("Task %s moved to the end of %q\n", c.TaskGID, section.Name)
+	}
+	return nil
+}
+
+// sectionIn returns the GID of the task's section in the given project, and
+// whether the task is in that project at all
+func sectionIn(memberships []api.Membership, projectGID string) (string, bool) {
+	for _, m := range memberships {
+		if m.Project == nil || m.Project.GID != projectGID {
+			continue
+		}
+		if m.Section == nil {
+			return "", true
+		}
+		return m.Section.GID, true
+	}
+	return "", false
+}
+
+// TasksSetParentCmd moves a task under another task
+type TasksSetParentCmd struct {
+	TaskGID     string `arg:"" help:"Task GID to move"`
+	ParentGID   string `arg:"" help:"GID of the new parent task"`
+	InsertAfter string `help:"Place the task after this subtask GID instead of at the end"`
+	JSON        bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *TasksSetParentCmd) Run(client *api.Client) error {
+	if c.TaskGID == c.ParentGID {
+		return fmt.Errorf("a task can't be its own parent")
+	}
+
+	task, err := client.SetParent(c.TaskGID, c.ParentGID, c.InsertAfter)
 	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if c.JSON {
+		return printJSON(task)
+	}
+
+	fmt.Printf("Task moved: %s -> parent %s\n", task.Name, c.ParentGID)
+	return nil
+}
+
+// TasksUnsetParentCmd promotes a subtask to a top-level task
+type TasksUnsetParentCmd struct {
+	TaskGID string `arg:"" help:"Subtask GID to promote"`
+	JSON    bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *TasksUnsetParentCmd) Run(client *api.Client) error {
+	task, err := client.RemoveParent(c.TaskGID)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	if c.JSON {
+		return printJSON(task)
+	}
+
+	fmt.Printf("Task is now top-level: %s\n", task.Name)
+	return nil
+}
+
+// TasksDuplicateCmd copies a task
+type TasksDuplicateCmd struct {
+	TaskGID string        `arg:"" optional:"" help:"Task GID to duplicate"`
+	Name    string        `short:"n" help:"Name of the new task (defaults to \"Copy of <name>\")"`
+	Include []string      `default:"notes,assignee,subtasks,attachments,dependencies" help:"Parts to copy: notes, assignee, subtasks, attachments, dependencies, tags, followers, projects, dates, parent"`
+	Timeout time.Duration `default:"2m" help:"How long to wait for the duplication to finish"`
+	JSON    bool          `short:"j" help:"Output as JSON"`
+}
+
+func (c *TasksDuplicateCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
+	name := c.Name
+	if name == "" {
+		task, err := client.GetTask(c.TaskGID)
+		if err != nil {
+			return notFound(err, "task", c.TaskGID)
+		}
+		name = "Copy of " + task.Name
+	}
+
+	job, err := client.DuplicateTask(c.TaskGID, api.DuplicateOptions{
+		Name:    name,
+		Include: c.Include,
+	})
+	if err != nil {
+		return err
+	}
+
+	job, err = client.WaitForJob(job.GID, c.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(job)
+	}
+
+	fmt.Printf("Task duplicated: %s\n", name)
+	if job.NewTask != nil {
+		fmt.Printf("GID: %s\n", job.NewTask.GID)
+	}
+	return nil
+}
+
+// TasksDeleteCmd deletes a task, given by GID or by name. The task is
+// fetched first so that the confirmation shows its name.
+type TasksDeleteCmd struct {
+	TaskGID           string `arg:"" optional:"" help:"Task GID to delete"`
+	Name              string `short:"n" help:"Delete the task with this exact name (case-insensitive) instead of giving a GID"`
+	PrintBeforeDelete bool   `help:"Print the task as JSON before deleting it, as a local backup (use --out to write it to a file)"`
+	Force             bool   `short:"f" help:"Skip confirmation (same as --yes)"`
+}
+
+func (c *TasksDeleteCmd) Run(client *api.Client, g *Globals) error {
+	if c.Name != "" {
+		if c.TaskGID != "" {
+			return fmt.Errorf("give either a task GID or --name, not both")
+		}
+		gid, err := resolveTaskName(client, g, c.Name)
+		if err != nil {
+			return err
+		}
+		c.TaskGID = gid
+	} else if err := pickTask(client, g, &c.TaskGID); err != nil {
 		return err
 	}
 
-	fmt.Printf("Task %s deleted.\n", c.TaskGID)
+	task, err := client.GetTask(c.TaskGID)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	// With the task printed to stdout, the status lines go to stderr so
+	// that the output stays valid JSON
+	status := io.Writer(os.Stdout)
+	if c.PrintBeforeDelete {
+		if err := printJSON(task); err != nil {
+			return err
+		}
+		status = os.Stderr
+	}
+
+	if !c.Force && !g.confirm(fmt.Sprintf("Are you sure you want to delete task %q (%s)?", task.Name, task.GID)) {
+		return nil
+	}
+
+	if err := client.DeleteTask(c.TaskGID); err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	fmt.Fprintf(status, "Task %q (%s) deleted.\n", task.Name, task.GID)
+	fmt.Fprintln(status, "Deleted tasks can be restored from Asana's trash in the web app for 30 days.")
 	return nil
 }
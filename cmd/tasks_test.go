@@ -0,0 +1,72 @@
+package cmd
+
+import "testing"
+
+func TestAddMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		isHTML  bool
+		refs    []string
+		gids    []string
+		want    string
+	}{
+		{
+			name:    "inline",
+			message: "thanks @alice!",
+			refs:    []string{"alice"},
+			gids:    []string{"1"},
+			want:    `<body>thanks <a data-asana-gid="1"/>!</body>`,
+		},
+		{
+			name:    "at the end",
+			message: "ping @alice",
+			refs:    []string{"alice"},
+			gids:    []string{"1"},
+			want:    `<body>ping <a data-asana-gid="1"/></body>`,
+		},
+		{
+			name:    "not named in the message",
+			message: "ready for review",
+			refs:    []string{"alice"},
+			gids:    []string{"1"},
+			want:    `<body>ready for review <a data-asana-gid="1"/></body>`,
+		},
+		{
+			name:    "prefix of another name",
+			message: "@alice and @al, please check",
+			refs:    []string{"al"},
+			gids:    []string{"2"},
+			want:    `<body>@alice and <a data-asana-gid="2"/>, please check</body>`,
+		},
+		{
+			name:    "only a prefix of another name",
+			message: "ask @alice",
+			refs:    []string{"al"},
+			gids:    []string{"2"},
+			want:    `<body>ask @alice <a data-asana-gid="2"/></body>`,
+		},
+		{
+			name:    "overlapping refs",
+			message: "@al @alice",
+			refs:    []string{"al", "alice"},
+			gids:    []string{"2", "1"},
+			want:    `<body><a data-asana-gid="2"/> <a data-asana-gid="1"/></body>`,
+		},
+		{
+			name:    "html",
+			message: "<body><b>@alice</b></body>",
+			isHTML:  true,
+			refs:    []string{"alice"},
+			gids:    []string{"1"},
+			want:    `<body><b><a data-asana-gid="1"/></b></body>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := addMentions(tt.message, tt.isHTML, tt.refs, tt.gids); got != tt.want {
+				t.Errorf("addMentions(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+// TasksTimeCmd shows or adds to the time tracked on a task
+type TasksTimeCmd struct {
+	TaskGID string `arg:"" optional:"" help:"Task GID"`
+	Add     string `help:"Add time spent, as a duration (90m, 1h30m)" xor:"time"`
+	Set     string `help:"Bring the tracked total up to a duration (2h) by adding the difference" xor:"time"`
+	Date    string `default:"today" help:"Date the time was spent (YYYY-MM-DD or today)"`
+	JSON    bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *TasksTimeCmd) Run(client *api.Client, g *Globals) error {
+	if err := pickTask(client, g, &c.TaskGID); err != nil {
+		return err
+	}
+
+	var minutes int
+	var err error
+	switch {
+	case c.Add != "":
+		if minutes, err = parseMinutes(c.Add); err != nil {
+			return fmt.Errorf("--add: %w", err)
+		}
+	case c.Set != "":
+		if minutes, err = parseMinutes(c.Set); err != nil {
+			return fmt.Errorf("--set: %w", err)
+		}
+	}
+
+	task, err := client.GetTask(c.TaskGID)
+	if err != nil {
+		return notFound(err, "task", c.TaskGID)
+	}
+
+	// Tracked time is the sum of time entries and can't be lowered by
+	// setting it, so --set adds whatever is missing
+	if c.Set != "" {
+		current := int(math.Round(task.ActualTimeMinutes))
+		if minutes < current {
+			return fmt.Errorf("%s is less than the %s already tracked; remove time entries in Asana to lower it",
+				formatMinutes(float64(minutes)), formatMinutes(task.ActualTimeMinutes))
+		}
+		minutes -= current
+		if minutes == 0 {
+			fmt.Printf("Time tracked on %s is already %s.\n", task.Name, formatMinutes(task.ActualTimeMinutes))
+			return nil
+		}
+	}
+
+	if minutes > 0 {
+		date, err := parseDueDate(c.Date, time.Now())
+		if err != nil {
+			return fmt.Errorf("--date: %w", err)
+		}
+		if err := client.AddTimeTrackingEntry(c.TaskGID, minutes, date); err != nil {
+			return err
+		}
+		task.ActualTimeMinutes += float64(minutes)
+	}
+
+	if c.JSON {
+		return printJSON(map[string]interface{}{
+			"gid":                 task.GID,
+			"name":                task.Name,
+			"added_minutes":       minutes,
+			"actual_time_minutes": task.ActualTimeMinutes,
+		})
+	}
+
+	if minutes > 0 {
+		fmt.Printf("Added %s to %s.\n", formatMinutes(float64(minutes)), task.Name)
+	} else {
+		fmt.Printf("Task: %s\n", task.Name)
+	}
+	fmt.Printf("Time tracked: %s\n", formatMinutes(task.ActualTimeMinutes))
+	return nil
+}
+
+// parseMinutes parses a Go-style duration (90m, 1h30m, 2h) into whole,
+// positive minutes
+func parseMinutes(s string) (int, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. 90m, 1h30m or 2h)", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	if d%time.Minute != 0 {
+		return 0, fmt.Errorf("duration must be in whole minutes")
+	}
+	return int(d / time.Minute), nil
+}
+
+// formatMinutes formats a number of minutes as e.g. "1h 30m"
+func formatMinutes(minutes float64) string {
+	m := int(math.Round(minutes))
+	switch {
+	case m < 60:
+		return fmt.Sprintf("%dm", m)
+	case m%60 == 0:
+		return fmt.Sprintf("%dh", m/60)
+	default:
+		return fmt.Sprintf("%dh %dm", m/60, m%60)
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+type TeamsCmd struct {
+	List TeamsListCmd `cmd:"" help:"List teams in the workspace"`
+}
+
+type TeamsListCmd struct {
+	Mine bool `short:"m" help:"Only list teams you are a member of"`
+	JSON bool `short:"j" help:"Output as JSON"`
+}
+
+func (c *TeamsListCmd) Run(client *api.Client, g *Globals) error {
+	teams, err := client.ListTeams(c.Mine)
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(teams)
+	}
+
+	if len(teams) == 0 {
+		fmt.Println("No teams found.")
+		return nil
+	}
+
+	t := newTable("GID", "NAME", "DESCRIPTION")
+	for _, team := range teams {
+		t.row(team.GID, team.Name, orDash(truncate(team.Description, 60)))
+	}
+
+	return t.print(g)
+}
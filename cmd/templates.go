@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+	"github.com/mauricejumelet/asana-cli/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+type TemplateCmd struct {
+	List  TemplateListCmd  `cmd:"" help:"List available task templates"`
+	Apply TemplateApplyCmd `cmd:"" help:"Create a task and its subtasks from a template"`
+}
+
+// taskTemplate describes a task and its subtasks. Templates are YAML or JSON
+// files in the templates directory, named after the template.
+type taskTemplate struct {
+	Name     string         `json:"name" yaml:"name"`
+	Notes    string         `json:"notes,omitempty" yaml:"notes,omitempty"`
+	Assignee string         `json:"assignee,omitempty" yaml:"assignee,omitempty"`
+	Project  string         `json:"project,omitempty" yaml:"project,omitempty"`
+	Tags     []string       `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Subtasks []taskTemplate `json:"subtasks,omitempty" yaml:"subtasks,omitempty"`
+}
+
+var templateExtensions = []string{".yaml", ".yml", ".json"}
+
+type TemplateListCmd struct{}
+
+func (c *TemplateListCmd) Run() error {
+	dir, err := config.TemplatesDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading templates: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		for _, known := range templateExtensions {
+			if !e.IsDir() && ext == known {
+				names = append(names, strings.TrimSuffix(e.Name(), ext))
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		fmt.Printf("No templates found in %s\n", dir)
+		return nil
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+type TemplateApplyCmd struct {
+	Template string            `arg:"" help:"Template name (file name without extension)"`
+	Project  string            `short:"p" help:"Project GID or name (overrides the template's project)"`
+	Var      map[string]string `help:"Template variable, used as {{key}} in names and notes (repeatable)" placeholder:"KEY=VALUE"`
+	JSON     bool              `short:"j" help:"Output as JSON"`
+}
+
+func (c *TemplateApplyCmd) Run(client *api.Client) error {
+	tmpl, err := loadTemplate(c.Template)
+	if err != nil {
+		return err
+	}
+
+	vars := map[string]string{
+		"date": time.Now().Format("2006-01-02"),
+	}
+	for k, v := range c.Var {
+		vars[k] = v
+	}
+
+	project := tmpl.Project
+	if c.Project != "" {
+		project = c.Project
+	}
+	project, err = resolveProject(client, project)
+	if err != nil {
+		return err
+	}
+
+	parent, err := createFromTemplate(client, *tmpl, vars, project, "")
+	if err != nil {
+		return err
+	}
+
+	var created []templateSubtask
+	if err := createSubtasks(client, tmpl.Subtasks, vars, parent.GID, 0, &created); err != nil {
+		return err
+	}
+
+	if c.JSON {
+		// Subtasks are listed depth first; nested ones name their parent
+		subtasks := make([]*api.Task, len(created))
+		for i, sub := range created {
+			subtasks[i] = sub.task
+		}
+		return printJSON(map[string]interface{}{
+			"task":     parent,
+			"subtasks": subtasks,
+		})
+	}
+
+	fmt.Printf("Task created from template %s!\n", c.Template)
+	fmt.Printf("GID: %s\n", parent.GID)
+	fmt.Printf("Name: %s\n", parent.Name)
+	if parent.Permalink != "" {
+		fmt.Printf("URL: %s\n", parent.Permalink)
+	}
+	if len(created) > 0 {
+		fmt.Printf("Subtasks (%d):\n", len(created))
+		for _, sub := range created {
+			fmt.Printf("%s  - %s [%s]\n", strings.Repeat("  ", sub.depth), sub.task.Name, sub.task.GID)
+		}
+	}
+	return nil
+}
+
+// templateSubtask is a subtask created from a template, with how deep it
+// is nested below the template's task
+type templateSubtask struct {
+	task  *api.Task
+	depth int
+}
+
+// createSubtasks creates subs as subtasks of parentGID, each followed by its
+// own subtasks, and appends them to created in that (depth-first) order
+func createSubtasks(client *api.Client, subs []taskTemplate, vars map[string]string, parentGID string, depth int, created *[]templateSubtask) error {
+	for _, sub := range subs {
+		task, err := createFromTemplate(client, sub, vars, "", parentGID)
+		if err != nil {
+			return fmt.Errorf("creating subtask %q: %w", sub.Name, err)
+		}
+		*created = append(*created, templateSubtask{task, depth})
+		if err := createSubtasks(client, sub.Subtasks, vars, task.GID, depth+1, created); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createFromTemplate creates a single task from a template entry, either in
+// a project or as a subtask of parentGID
+func createFromTemplate(client *api.Client, tmpl taskTemplate, vars map[string]string, projectGID, parentGID string) (*api.Task, error) {
+	assignee, err := resolveUser(client, tmpl.Assignee)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := api.CreateTaskOptions{
+		Name:     expandVars(tmpl.Name, vars),
+		Notes:    expandVars(tmpl.Notes, vars),
+		Assignee: assignee,
+		Tags:     tmpl.Tags,
+		Parent:   parentGID,
+	}
+	if projectGID != "" {
+		opts.Projects = []string{projectGID}
+	}
+
+	return client.CreateTask(opts)
+}
+
+// loadTemplate reads the named template from the templates directory
+func loadTemplate(name string) (*taskTemplate, error) {
+	dir, err := config.TemplatesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ext := range templateExtensions {
+		path := filepath.Join(dir, name+ext)
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading template: %w", err)
+		}
+
+		var tmpl taskTemplate
+		if ext == ".json" {
+			err = json.Unmarshal(data, &tmpl)
+		} else {
+			err = yaml.Unmarshal(data, &tmpl)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing template %s: %w", path, err)
+		}
+		if tmpl.Name == "" {
+			return nil, fmt.Errorf("template %s has no name", path)
+		}
+		return &tmpl, nil
+	}
+
+	return nil, fmt.Errorf("template %q not found in %s (run 'asana template list')", name, dir)
+}
+
+// expandVars replaces {{key}} placeholders with their values
+func expandVars(s string, vars map[string]string) string {
+	for k, v := range vars {
+		s = strings.ReplaceAll(s, "{{"+k+"}}", v)
+	}
+	return s
+}
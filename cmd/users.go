@@ -1,24 +1,38 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"text/tabwriter"
+	"net/http"
+	"strings"
 
 	"github.com/mauricejumelet/asana-cli/internal/api"
 )
 
 type UsersCmd struct {
-	List UsersListCmd `cmd:"" help:"List users in the workspace"`
-	Me   UsersMeCmd   `cmd:"" help:"Show current user"`
+	List  UsersListCmd  `cmd:"" help:"List users in the workspace"`
+	Me    UsersMeCmd    `cmd:"" help:"Show current user"`
+	Get   UsersGetCmd   `cmd:"" help:"Show a user by GID, email or name"`
+	Tasks UsersTasksCmd `cmd:"" help:"List a user's My Tasks in the order they arranged them"`
 }
 
 type UsersListCmd struct {
-	JSON bool `short:"j" help:"Output as JSON"`
+	Fields string `help:"Comma-separated columns to show (gid,name,email)"`
+	JSON   bool   `short:"j" help:"Output as JSON"`
 }
 
-func (c *UsersListCmd) Run(client *api.Client) error {
-	users, err := client.ListUsers()
+func (c *UsersListCmd) Run(client *api.Client, g *Globals) error {
+	fields, err := userColumns.parse(c.Fields)
+	if err != nil {
+		return err
+	}
+
+	optFields := ""
+	if c.Fields != "" {
+		optFields = userColumns.optFields(fields)
+	}
+
+	users, err := client.ListUsers(optFields)
 	if err != nil {
 		return err
 	}
@@ -32,19 +46,74 @@ func (c *UsersListCmd) Run(client *api.Client) error {
 		return nil
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "GID\tNAME\tEMAIL")
-	fmt.Fprintln(w, "---\t----\t-----")
+	return userColumns.table(users, fields).print(g)
+}
+
+type UsersGetCmd struct {
+	User  string `arg:"" help:"User GID, email, name or 'me'"`
+	Tasks bool   `help:"Also count the open tasks assigned to the user in this workspace"`
+	JSON  bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *UsersGetCmd) Run(client *api.Client) error {
+	gid, err := resolveUser(client, c.User)
+	if err != nil {
+		return err
+	}
+
+	var (
+		user  *api.User
+		tasks []api.Task
+	)
+	fetches := []func(ctx context.Context) error{
+		func(ctx context.Context) (err error) {
+			user, err = client.WithContext(ctx).GetUser(gid)
+			return err
+		},
+	}
+	if c.Tasks {
+		fetches = append(fetches, func(ctx context.Context) (err error) {
+			tasks, err = client.WithContext(ctx).ListTasks(api.TaskListOptions{
+				Assignee:        gid,
+				IncludeSubtasks: true,
+				OptFields:       "gid",
+			})
+			return err
+		})
+	}
+	if err := runParallel(fetches...); err != nil {
+		return notFound(err, "user", gid)
+	}
 
-	for _, user := range users {
-		email := "-"
-		if user.Email != "" {
-			email = user.Email
+	if c.JSON {
+		if !c.Tasks {
+			return printJSON(user)
 		}
-		fmt.Fprintf(w, "%s\t%s\t%s\n", user.GID, user.Name, email)
+		return printJSON(map[string]interface{}{
+			"user":       user,
+			"open_tasks": len(tasks),
+		})
+	}
+
+	fmt.Printf("Name: %s\n", user.Name)
+	fmt.Printf("GID: %s\n", user.GID)
+	if user.Email != "" {
+		fmt.Printf("Email: %s\n", user.Email)
+	}
+	if len(user.Workspaces) > 0 {
+		names := make([]string, len(user.Workspaces))
+		for i, w := range user.Workspaces {
+			names[i] = w.Name
+		}
+		fmt.Printf("Workspaces: %s\n", strings.Join(names, ", "))
+	}
+	if url := user.Photo["image_128x128"]; url != "" {
+		fmt.Printf("Photo: %s\n", url)
+	}
+	if c.Tasks {
+		fmt.Printf("Open tasks: %d\n", len(tasks))
 	}
 
-	w.Flush()
 	return nil
 }
 
@@ -70,3 +139,32 @@ func (c *UsersMeCmd) Run(client *api.Client) error {
 
 	return nil
 }
+
+// UsersTasksCmd shows another user's My Tasks list. Unlike tasks list -a,
+// it keeps that user's own order and My Tasks sections.
+type UsersTasksCmd struct {
+	User  string `arg:"" help:"User GID, email, name or 'me'"`
+	Limit int    `short:"l" default:"100" help:"Maximum number of tasks to return (0 for no limit)"`
+	JSON  bool   `short:"j" help:"Output as JSON"`
+	OptFieldsFlags
+}
+
+func (c *UsersTasksCmd) Run(client *api.Client, g *Globals) error {
+	gid, err := resolveUser(client, c.User)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := c.withOptFields(client).ListTasksByUser(gid, c.Limit)
+	if api.IsStatus(err, http.StatusForbidden) {
+		return fmt.Errorf("you don't have access to %s's task list; My Tasks lists are private unless shared (use tasks list -a to see their tasks in projects you can access)", c.User)
+	}
+	if err != nil {
+		return notFound(err, "user", c.User)
+	}
+
+	if c.JSON {
+		return printJSON(tasks)
+	}
+	return printUserTaskList(g, tasks, c.Limit)
+}
@@ -1,16 +1,333 @@
 package cmd
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alecthomas/kong"
+	"github.com/mauricejumelet/asana-cli/internal/api"
+	"gopkg.in/yaml.v3"
 )
 
+// ExplainConflict adds a hint to conflict errors to try again or use
+// --retry-on-conflict, unless retrying says that flag was already given.
+// Other errors are returned unchanged.
+func ExplainConflict(err error, retrying bool) error {
+	if retrying || !errors.Is(err, api.ErrConflict) {
+		return err
+	}
+	return fmt.Errorf("%w (try again, or use --retry-on-conflict)", err)
+}
+
+// confirm asks a yes/no question on stdin and reports whether the answer
+// was yes. --yes answers it without asking; end of input (e.g. stdin not
+// a terminal) counts as no. The prompt goes to stderr, so that it doesn't
+// end up in JSON printed to stdout.
+func (g *Globals) confirm(prompt string) bool {
+	if g.Yes {
+		return true
+	}
+
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(os.Stderr)
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Fprintln(os.Stderr, "Cancelled.")
+	return false
+}
+
+// printJSON writes v as indented JSON to dataOut, or as YAML with --yaml
 func printJSON(v interface{}) error {
-	enc := json.NewEncoder(os.Stdout)
+	if outputYAML {
+		return printYAML(v)
+	}
+
+	enc := json.NewEncoder(dataOut)
 	enc.SetIndent("", "  ")
 	if err := enc.Encode(v); err != nil {
 		return fmt.Errorf("encoding JSON: %w", err)
 	}
 	return nil
 }
+
+// printYAML writes v as YAML to dataOut. v is converted through JSON so
+// that the keys, their order and omitted fields match --json exactly.
+func printYAML(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding YAML: %w", err)
+	}
+
+	// JSON is valid YAML, so parsing it keeps the key order; resetting the
+	// styles turns its flow syntax and quoted strings into block YAML
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("encoding YAML: %w", err)
+	}
+	clearYAMLStyle(&doc)
+
+	enc := yaml.NewEncoder(dataOut)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("encoding YAML: %w", err)
+	}
+	return enc.Close()
+}
+
+// clearYAMLStyle resets the style of n and the nodes below it to yaml.v3's
+// defaults. Strings that a YAML 1.1 parser (PyYAML, older Ruby and Go
+// libraries) would read as a bool or null keep their double quotes, which
+// yaml.v3 only adds for YAML 1.2's true, false and null.
+func clearYAMLStyle(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!str" && yaml11Keywords[strings.ToLower(n.Value)] {
+		n.Style = yaml.DoubleQuotedStyle
+	} else {
+		n.Style = 0
+	}
+	for _, c := range n.Content {
+		clearYAMLStyle(c)
+	}
+}
+
+// yaml11Keywords are the YAML 1.1 bool and null words, lower-cased
+var yaml11Keywords = map[string]bool{
+	"y": true, "yes": true, "n": true, "no": true,
+	"true": true, "false": true, "on": true, "off": true,
+	"null": true, "~": true,
+}
+
+// jsonEnvelope wraps list results with paging metadata for --json-meta
+type jsonEnvelope struct {
+	Data       interface{} `json:"data"`
+	Count      int         `json:"count"`
+	NextOffset *string     `json:"next_offset"`
+	Workspace  string      `json:"workspace"`
+}
+
+// printJSONEnvelope prints list results wrapped in a jsonEnvelope. The next
+// offset is null when there are no further pages.
+func printJSONEnvelope(data interface{}, count int, next *api.Page, workspace string) error {
+	env := jsonEnvelope{
+		Data:      data,
+		Count:     count,
+		Workspace: workspace,
+	}
+	if next != nil && next.Offset != "" {
+		env.NextOffset = &next.Offset
+	}
+	return printJSON(env)
+}
+
+// searchEnvelope is the --json-meta wrapper of task searches. The search
+// API has no pages to continue, so there is no next_offset.
+type searchEnvelope struct {
+	Data      []api.Task `json:"data"`
+	Count     int        `json:"count"`
+	Workspace string     `json:"workspace"`
+}
+
+// printSearchEnvelope prints tasks wrapped in a searchEnvelope
+func printSearchEnvelope(tasks []api.Task, workspace string) error {
+	if tasks == nil {
+		tasks = []api.Task{}
+	}
+	return printJSON(searchEnvelope{Data: tasks, Count: len(tasks), Workspace: workspace})
+}
+
+// explainAfter adds a hint to a rejected request that was resumed with
+// --after, since an offset is only valid for the query that produced it
+func explainAfter(err error, after string) error {
+	if after != "" && api.IsStatus(err, http.StatusBadRequest) {
+		return fmt.Errorf("%w (an --after offset only works with the same filters and --limit that produced it)", err)
+	}
+	return err
+}
+
+// flagProvided reports whether the named flag was given on the command line.
+// A value that came from a default: or env: tag doesn't count; kong marks
+// those flags as set too, so the parse path is checked instead.
+func flagProvided(ctx *kong.Context, name string) bool {
+	for _, p := range ctx.Path {
+		if p.Flag != nil && !p.Resolved && p.Flag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// table renders aligned columns with a header row. Output is buffered and
+// written through the pager when printed.
+type table struct {
+	rows   [][]string
+	footer []string
+}
+
+func newTable(headers ...string) *table {
+	t := &table{}
+
+	dashes := make([]string, len(headers))
+	for i, h := range headers {
+		dashes[i] = strings.Repeat("-", displayWidth(h))
+	}
+	t.row(headers...)
+	t.row(dashes...)
+
+	return t
+}
+
+// row adds a row to the table
+func (t *table) row(values ...string) {
+	t.rows = append(t.rows, values)
+}
+
+// render writes the rows to buf with every column but the last padded to
+// its widest cell, measured in terminal columns so that wide characters
+// (CJK, emoji) keep the table aligned
+func (t *table) render(buf *bytes.Buffer) {
+	var widths []int
+	for _, r := range t.rows {
+		for i, v := range r {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], displayWidth(v))
+		}
+	}
+
+	for _, r := range t.rows {
+		for i, v := range r {
+			if i < len(r)-1 {
+				v = padRight(v, widths[i]+2)
+			}
+			buf.WriteString(v)
+		}
+		buf.WriteString("\n")
+	}
+}
+
+// footerf adds a line printed after the table, separated by a blank line
+func (t *table) footerf(format string, args ...interface{}) {
+	t.footer = append(t.footer, fmt.Sprintf(format, args...))
+}
+
+// print writes the table and its footer to stdout
+func (t *table) print(g *Globals) error {
+	var buf bytes.Buffer
+	t.render(&buf)
+	if len(t.footer) > 0 {
+		buf.WriteString("\n" + strings.Join(t.footer, "\n") + "\n")
+	}
+	return pageOutput(g, buf.Bytes())
+}
+
+// runParallel runs fns concurrently and returns the first error. The
+// context passed to each fn is cancelled as soon as any of them fails.
+func runParallel(fns ...func(ctx context.Context) error) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, fn := range fns {
+		wg.Add(1)
+		go func(fn func(ctx context.Context) error) {
+			defer wg.Done()
+			if err := fn(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(fn)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// runPool calls fn for each index below n, on at most workers goroutines
+// at a time (at least one), and returns fn's results in index order.
+// Unlike runParallel, a failing item doesn't stop the others, so batch
+// commands can report every failure.
+func runPool[R any](n, workers int, fn func(i int) R) []R {
+	results := make([]R, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < max(workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// printQuietResult prints one result of a batch command for --quiet: the
+// GID on stdout, or the failure of name on stderr, so that scripts reading
+// stdout only see what succeeded
+func printQuietResult(name, gid, errMsg string) {
+	if errMsg != "" {
+		fmt.Fprintf(os.Stderr, "FAILED %s: %s\n", name, errMsg)
+		return
+	}
+	fmt.Println(gid)
+}
+
+// parseSince turns a date (YYYY-MM-DD) or a relative time such as "7d",
+// "2w" or "24h" into a value for the API's *.after filters. Dates are
+// returned unchanged; relative times become an RFC 3339 timestamp, and a
+// zero time such as "0d" means now.
+func parseSince(s string, now time.Time) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", nil
+	}
+	if _, err := time.Parse("2006-01-02", s); err == nil {
+		return s, nil
+	}
+
+	var d time.Duration
+	days := 0
+	switch s[len(s)-1] {
+	case 'd':
+		days = 1
+	case 'w':
+		days = 7
+	}
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 && days > 0 {
+		d = time.Duration(n*days) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil || d < 0 {
+			return "", fmt.Errorf("invalid time %q (use YYYY-MM-DD or a relative time like 7d or 24h)", s)
+		}
+	}
+
+	return now.Add(-d).UTC().Format(time.RFC3339), nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: "2024-05-01", want: "2024-05-01"},
+		{in: "7d", want: "2024-05-08T12:00:00Z"},
+		{in: "2w", want: "2024-05-01T12:00:00Z"},
+		{in: "24h", want: "2024-05-14T12:00:00Z"},
+		{in: "0d", want: "2024-05-15T12:00:00Z"},
+		{in: "0w", want: "2024-05-15T12:00:00Z"},
+		{in: "0s", want: "2024-05-15T12:00:00Z"},
+		{in: "-1d", wantErr: true},
+		{in: "d", wantErr: true},
+		{in: "yesterday", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseSince(tt.in, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSince(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseSince(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
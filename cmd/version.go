@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// latestReleaseURL is the GitHub API endpoint for the newest release
+const latestReleaseURL = "https://api.github.com/repos/mjumelet/asana-cli/releases/latest"
+
+// BuildInfo describes the running binary. Release builds get the commit
+// and date from ldflags; other builds fall back to what Go embedded.
+type BuildInfo struct {
+	Version string
+	Commit  string
+	Date    string
+}
+
+// VersionCmd prints version and build details, and optionally checks
+// GitHub for a newer release
+type VersionCmd struct {
+	Check bool `help:"Check GitHub for a newer release"`
+}
+
+func (c *VersionCmd) Run(info BuildInfo) error {
+	commit, date := info.Commit, info.Date
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && commit == "":
+				commit = s.Value
+				if len(commit) > 7 {
+					commit = commit[:7]
+				}
+			case s.Key == "vcs.time" && date == "":
+				date = s.Value
+			}
+		}
+	}
+
+	fmt.Printf("asana-cli v%s\n", info.Version)
+	fmt.Printf("Go: %s (%s/%s)\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if commit != "" {
+		fmt.Printf("Commit: %s\n", commit)
+	}
+	if date != "" {
+		fmt.Printf("Date: %s\n", date)
+	}
+
+	if !c.Check {
+		return nil
+	}
+
+	latest, url, err := latestRelease()
+	if err != nil {
+		return fmt.Errorf("checking for updates: %w", err)
+	}
+	if newerVersion(latest, info.Version) {
+		fmt.Printf("\nUpdate available: v%s (you have v%s)\n", latest, info.Version)
+		fmt.Printf("Release notes: %s\n", url)
+		fmt.Println("Upgrade with: brew upgrade asana-cli, or download it from the release page")
+	} else {
+		fmt.Printf("\nYou have the latest version (v%s).\n", latest)
+	}
+	return nil
+}
+
+// latestRelease returns the version and page of the newest GitHub release
+func latestRelease() (string, string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequest("GET", latestReleaseURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("GitHub returned %s", resp.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", "", fmt.Errorf("parsing response: %w", err)
+	}
+	if release.TagName == "" {
+		return "", "", fmt.Errorf("no release found")
+	}
+
+	return strings.TrimPrefix(release.TagName, "v"), release.HTMLURL, nil
+}
+
+// newerVersion reports whether version a (e.g. "1.4.0") is newer than b.
+// Missing or non-numeric parts count as 0.
+func newerVersion(a, b string) bool {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return false
+}
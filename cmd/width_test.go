@@ -0,0 +1,113 @@
+package cmd
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"empty", "", 0},
+		{"ascii", "hello", 5},
+		{"cjk", "日本語", 6},
+		{"hangul", "한국", 4},
+		{"fullwidth", "ＡＢ", 4},
+		{"emoji", "🚀 go", 5},
+		{"combining mark", "e\u0301te", 3},
+		{"zero width joiner", "👩‍💻", 4},
+		{"ansi", colorRed + "late" + colorReset, 4},
+		{"ansi around cjk", "\033[1;31m日本\033[0m", 4},
+		{"control character", "a\tb", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.s); got != tt.want {
+				t.Errorf("displayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCutWidth(t *testing.T) {
+	tests := []struct {
+		name       string
+		s          string
+		width      int
+		head, tail string
+	}{
+		{"fits", "abc", 5, "abc", ""},
+		{"exact", "abc", 3, "abc", ""},
+		{"ascii", "abcdef", 4, "abcd", "ef"},
+		{"zero width", "abc", 0, "", "abc"},
+		{"cjk", "日本語", 4, "日本", "語"},
+		{"cjk doesn't split a wide character", "日本語", 3, "日", "本語"},
+		{"emoji", "🚀🚀", 3, "🚀", "🚀"},
+		{"combining mark stays with its letter", "e\u0301x", 1, "e\u0301", "x"},
+		{"ansi takes no columns", colorRed + "abcd" + colorReset, 2, colorRed + "ab", "cd" + colorReset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head, tail := cutWidth(tt.s, tt.width)
+			if head != tt.head || tail != tt.tail {
+				t.Errorf("cutWidth(%q, %d) = %q, %q, want %q, %q", tt.s, tt.width, head, tail, tt.head, tt.tail)
+			}
+		})
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"ascii", "ab", 4, "ab  "},
+		{"already wide enough", "abcd", 2, "abcd"},
+		{"cjk", "日本", 6, "日本  "},
+		{"emoji", "🚀", 3, "🚀 "},
+		{"combining mark", "e\u0301", 3, "e\u0301  "},
+		{"ansi", colorRed + "ab" + colorReset, 3, colorRed + "ab" + colorReset + " "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := padRight(tt.s, tt.width); got != tt.want {
+				t.Errorf("padRight(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name   string
+		s      string
+		maxLen int
+		want   string
+	}{
+		{"fits", "hello", 5, "hello"},
+		{"ascii", "hello world", 8, "hello..."},
+		{"cjk", "日本語のタスク", 7, "日本..."},
+		{"cjk doesn't split a wide character", "日本語のタスク", 8, "日本..."},
+		{"emoji", "🚀🚀🚀🚀", 6, "🚀..."},
+		{"combining mark", "cafe\u0301 au lait", 7, "cafe\u0301..."},
+		{"ansi fits", colorRed + "late" + colorReset, 4, colorRed + "late" + colorReset},
+		{"exactly three", "hello", 3, "..."},
+		{"two columns", "hello", 2, "he"},
+		{"one column", "hello", 1, "h"},
+		{"zero columns", "hello", 0, ""},
+		{"wide character in one column", "日本", 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := truncate(tt.s, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("truncate(%q, %d) = %q, want %q", tt.s, tt.maxLen, got, tt.want)
+			}
+			if w := displayWidth(got); w > max(tt.maxLen, 0) {
+				t.Errorf("truncate(%q, %d) is %d columns wide", tt.s, tt.maxLen, w)
+			}
+		})
+	}
+}
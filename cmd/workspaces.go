@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mauricejumelet/asana-cli/internal/api"
+)
+
+type WorkspacesCmd struct {
+	List WorkspacesListCmd `cmd:"" help:"List workspaces you have access to"`
+	Get  WorkspacesGetCmd  `cmd:"" help:"Show details of a workspace"`
+}
+
+type WorkspacesListCmd struct {
+	JSON bool `short:"j" help:"Output as JSON"`
+}
+
+func (c *WorkspacesListCmd) Run(client *api.Client, g *Globals) error {
+	workspaces, err := client.ListWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(workspaces)
+	}
+
+	if len(workspaces) == 0 {
+		fmt.Println("No workspaces found.")
+		return nil
+	}
+
+	t := newTable("GID", "NAME", "ORGANIZATION", "ACTIVE")
+	for _, w := range workspaces {
+		org := "No"
+		if w.IsOrganization {
+			org = "Yes"
+		}
+		active := ""
+		if w.GID == client.Workspace() {
+			active = "*"
+		}
+		t.row(w.GID, w.Name, org, active)
+	}
+
+	return t.print(g)
+}
+
+// WorkspacesGetCmd shows a workspace's details, notably whether it is an
+// organization, which changes how creating projects and teams works
+type WorkspacesGetCmd struct {
+	Workspace string `arg:"" optional:"" help:"Workspace GID or name (default: the configured workspace)"`
+	JSON      bool   `short:"j" help:"Output as JSON"`
+}
+
+func (c *WorkspacesGetCmd) Run(client *api.Client) error {
+	gid := client.Workspace()
+	if c.Workspace != "" {
+		var err error
+		if gid, err = ResolveWorkspace(client, c.Workspace); err != nil {
+			return err
+		}
+	}
+	if gid == "" {
+		return fmt.Errorf("no workspace configured (set ASANA_WORKSPACE or pass a workspace)")
+	}
+
+	workspace, err := client.GetWorkspace(gid)
+	if err != nil {
+		return notFound(err, "workspace", gid)
+	}
+
+	if c.JSON {
+		return printJSON(workspace)
+	}
+
+	fmt.Printf("Workspace: %s\n", workspace.Name)
+	fmt.Printf("GID: %s\n", workspace.GID)
+	fmt.Printf("Type: %s\n", workspaceKind(workspace))
+	if len(workspace.EmailDomains) > 0 {
+		fmt.Printf("Email domains: %s\n", strings.Join(workspace.EmailDomains, ", "))
+	}
+	return nil
+}
+
+// workspaceKind describes whether a workspace is an organization
+func workspaceKind(w *api.Workspace) string {
+	if w.IsOrganization {
+		return "organization"
+	}
+	return "workspace"
+}
@@ -3,6 +3,11 @@ module github.com/mauricejumelet/asana-cli
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/alecthomas/kong v1.2.1
 	github.com/joho/godotenv v1.5.1
+	golang.org/x/term v0.27.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require golang.org/x/sys v0.28.0 // indirect
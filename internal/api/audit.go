@@ -0,0 +1,124 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// auditLog appends a JSON line for every mutating request to a file, as a
+// record of what was changed. Tokens and request bodies are never written.
+type auditLog struct {
+	mu      sync.Mutex
+	path    string
+	command string
+}
+
+type auditEntry struct {
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	Target   string    `json:"target,omitempty"`
+	Success  bool      `json:"success"`
+	Status   int       `json:"status,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	User     string    `json:"user,omitempty"`
+}
+
+// EnableAuditLog appends a line to the file at path for every request that
+// changes something. command names the CLI command being run.
+func (c *Client) EnableAuditLog(path, command string) {
+	c.audit = &auditLog{path: path, command: command}
+}
+
+// record writes the outcome of a mutating request. A failure to write is
+// reported on stderr rather than failing a request that already happened.
+func (a *auditLog) record(c *Client, req *http.Request, respBody []byte, reqErr error) {
+	entry := auditEntry{
+		Time:     time.Now().UTC(),
+		Command:  a.command,
+		Method:   req.Method,
+		Endpoint: auditEndpoint(c.baseURL, req.URL),
+		Success:  reqErr == nil,
+	}
+	entry.Target = auditTarget(entry.Endpoint, respBody)
+	if reqErr != nil {
+		var apiErr *APIError
+		if errors.As(reqErr, &apiErr) {
+			entry.Status = apiErr.StatusCode
+		}
+		entry.Error = reqErr.Error()
+	}
+	// Looked up once per run; left out if the lookup fails (e.g. a bad token)
+	if gid, err := c.MyGID(); err == nil {
+		entry.User = gid
+	}
+
+	// Encoder adds the newline; command names contain <args>, kept readable
+	var line bytes.Buffer
+	enc := json.NewEncoder(&line)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(entry)
+	if err == nil {
+		err = a.append(line.Bytes())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: writing audit log %s: %v\n", a.path, err)
+	}
+}
+
+func (a *auditLog) append(line []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// auditEndpoint returns the request path relative to the API base URL,
+// without the query string
+func auditEndpoint(baseURL string, u *url.URL) string {
+	if base, err := url.Parse(baseURL); err == nil {
+		return "/" + strings.TrimPrefix(strings.TrimPrefix(u.Path, strings.TrimRight(base.Path, "/")), "/")
+	}
+	return u.Path
+}
+
+// auditTarget returns the GID a request acted on: the first GID in the
+// path other than the workspace's, or for creations the GID in the response
+func auditTarget(endpoint string, respBody []byte) string {
+	parts := strings.Split(strings.Trim(endpoint, "/"), "/")
+	for i, p := range parts {
+		if i > 0 && parts[i-1] == "workspaces" {
+			continue
+		}
+		if p != "" && strings.Trim(p, "0123456789") == "" {
+			return p
+		}
+	}
+
+	var resp struct {
+		Data struct {
+			GID string `json:"gid"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(respBody, &resp) == nil {
+		return resp.Data.GID
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CacheDir returns the directory where cached API responses are stored
+func CacheDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".cache", "asana-cli"), nil
+}
+
+// ClearCache removes all cached API responses
+func ClearCache() error {
+	dir, err := CacheDir()
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("removing cache: %w", err)
+	}
+	return nil
+}
+
+// DefaultCacheTTL is how long responses are cached when caching is turned
+// on with --cache but no duration is configured
+const DefaultCacheTTL = 60 * time.Second
+
+// responseCache stores GET response bodies on disk for a short time.
+// Entries are keyed by token and endpoint so accounts never share data.
+// Once expired, an entry the API sent an ETag or Last-Modified for is kept
+// a while longer so the next request can be made conditional.
+type responseCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// revalidateAge is how long an expired entry with validators is kept for
+// conditional requests
+const revalidateAge = 24 * time.Hour
+
+type cacheEntry struct {
+	Endpoint     string          `json:"endpoint"`
+	StoredAt     time.Time       `json:"stored_at"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// canRevalidate reports whether the entry can be checked with a
+// conditional request
+func (e *cacheEntry) canRevalidate() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+// expired reports whether the entry is of no further use
+func (e *cacheEntry) expired(ttl time.Duration) bool {
+	age := time.Since(e.StoredAt)
+	if e.canRevalidate() {
+		return age > ttl+revalidateAge
+	}
+	return age > ttl
+}
+
+// conditionalHeaders returns the If-None-Match and If-Modified-Since
+// headers that ask the API to answer 304 if the entry is still current
+func (e *cacheEntry) conditionalHeaders() http.Header {
+	h := http.Header{}
+	if e.ETag != "" {
+		h.Set("If-None-Match", e.ETag)
+	}
+	if e.LastModified != "" {
+		h.Set("If-Modified-Since", e.LastModified)
+	}
+	return h
+}
+
+// EnableCache turns on caching of GET responses in dir for ttl
+func (c *Client) EnableCache(dir string, ttl time.Duration) {
+	c.cache = &responseCache{dir: dir, ttl: ttl}
+}
+
+// path returns the file for endpoint. scope keeps entries of different
+// accounts and servers apart.
+func (rc *responseCache) path(scope, endpoint string) string {
+	sum := sha256.Sum256([]byte(scope + "\n" + endpoint))
+	return filepath.Join(rc.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the cached entry for endpoint and whether it is still fresh.
+// A stale entry is only returned if it can be revalidated.
+func (rc *responseCache) get(scope, endpoint string) (*cacheEntry, bool) {
+	data, err := os.ReadFile(rc.path(scope, endpoint))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	if entry.Endpoint != endpoint || entry.expired(rc.ttl) {
+		return nil, false
+	}
+
+	return &entry, time.Since(entry.StoredAt) <= rc.ttl
+}
+
+// put stores a response body along with its ETag and Last-Modified
+// validators, if any. Failures are ignored since the cache is only an
+// optimization.
+func (rc *responseCache) put(scope, endpoint string, body []byte, etag, lastModified string) {
+	if !json.Valid(body) {
+		return
+	}
+
+	data, err := json.Marshal(cacheEntry{
+		Endpoint:     endpoint,
+		StoredAt:     time.Now(),
+		ETag:         etag,
+		LastModified: lastModified,
+		Body:         body,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(rc.dir, 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(rc.path(scope, endpoint), data, 0600)
+}
+
+// invalidate drops entries that a mutation of endpoint may have made stale:
+// every entry mentioning one of the GIDs in the mutated path, and every
+// collection listing (search, project tasks, ...). Expired entries are
+// removed along the way.
+func (rc *responseCache) invalidate(endpoint string) {
+	files, err := os.ReadDir(rc.dir)
+	if err != nil {
+		return
+	}
+
+	gids := pathGIDs(endpoint)
+
+	for _, f := range files {
+		path := filepath.Join(rc.dir, f.Name())
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var entry cacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.expired(rc.ttl) {
+			os.Remove(path)
+			continue
+		}
+
+		stale := !isResourcePath(entry.Endpoint)
+		for gid := range pathGIDs(entry.Endpoint) {
+			if gids[gid] {
+				stale = true
+			}
+		}
+		if stale {
+			os.Remove(path)
+		}
+	}
+}
+
+// pathGIDs returns the numeric path segments of an endpoint
+func pathGIDs(endpoint string) map[string]bool {
+	gids := map[string]bool{}
+	for _, seg := range strings.Split(endpointPath(endpoint), "/") {
+		if seg != "" && strings.Trim(seg, "0123456789") == "" {
+			gids[seg] = true
+		}
+	}
+	return gids
+}
+
+// isResourcePath reports whether endpoint addresses a single resource
+// (e.g. /tasks/123) rather than a collection
+func isResourcePath(endpoint string) bool {
+	path := endpointPath(endpoint)
+	last := path[strings.LastIndex(path, "/")+1:]
+	return last != "" && strings.Trim(last, "0123456789") == ""
+}
+
+func endpointPath(endpoint string) string {
+	if i := strings.Index(endpoint, "?"); i >= 0 {
+		return endpoint[:i]
+	}
+	return endpoint
+}
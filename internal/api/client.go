@@ -2,33 +2,75 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mauricejumelet/asana-cli/internal/config"
 )
 
-const baseURL = "https://app.asana.com/api/1.0"
+// DefaultBaseURL is the Asana API endpoint used unless another is configured
+const DefaultBaseURL = "https://app.asana.com/api/1.0"
 
 type Client struct {
-	httpClient *http.Client
-	token      string
-	workspace  string
+	httpClient     *http.Client
+	baseURL        string
+	token          string
+	workspace      string
+	debug          io.Writer
+	cache          *responseCache
+	audit          *auditLog
+	retryConflicts bool
+	optFields      string // replaces the default opt_fields; see WithOptFields
+	optFieldsAdd   string // added to the default opt_fields
+	ctx            context.Context
+	me             *currentUser    // shared with copies made by WithContext
+	limiter        *requestLimiter // shared with copies, bounds requests in flight
+}
+
+// currentUser caches the GID of the authenticated user
+type currentUser struct {
+	mu  sync.Mutex
+	gid string
 }
 
 func NewClient(cfg *config.Config) *Client {
+	return NewClientWithHTTP(cfg, &http.Client{})
+}
+
+// NewClientWithHTTP creates a client that sends requests through
+// httpClient, e.g. one with a custom transport for proxies or tests.
+// A nil httpClient behaves like NewClient.
+func NewClientWithHTTP(cfg *config.Config, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	baseURL := strings.TrimRight(cfg.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
 	return &Client{
-		httpClient: &http.Client{},
+		httpClient: httpClient,
+		baseURL:    baseURL,
 		token:      cfg.Token,
 		workspace:  cfg.Workspace,
+		me:         &currentUser{},
+		limiter:    newRequestLimiter(DefaultMaxConcurrentRequests),
 	}
 }
 
@@ -36,12 +78,136 @@ func (c *Client) Workspace() string {
 	return c.workspace
 }
 
+// BaseURL returns the API base URL requests are sent to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// WithContext returns a copy of the client whose requests are bound to ctx,
+// so they are aborted when ctx is cancelled
+func (c *Client) WithContext(ctx context.Context) *Client {
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// WithOptFields returns a copy of the client whose task and project
+// listings and lookups request replace instead of their usual opt_fields,
+// or the usual ones plus add. Fields the structs don't model are kept and
+// show up in JSON output.
+func (c *Client) WithOptFields(replace, add string) *Client {
+	c2 := *c
+	c2.optFields = replace
+	c2.optFieldsAdd = add
+	return &c2
+}
+
+// fields applies the WithOptFields override to a default opt_fields list
+func (c *Client) fields(defaults string) string {
+	switch {
+	case c.optFields != "":
+		return c.optFields
+	case c.optFieldsAdd != "":
+		return defaults + "," + c.optFieldsAdd
+	}
+	return defaults
+}
+
+// context returns the context requests should use
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// cacheScope keeps cached responses of different accounts and servers apart
+func (c *Client) cacheScope() string {
+	return c.baseURL + "\n" + c.token
+}
+
+// SetWorkspace changes the workspace that requests operate on
+func (c *Client) SetWorkspace(gid string) {
+	c.workspace = gid
+}
+
+// SetRetryOnConflict makes updates (PUT requests) that fail with a conflict
+// (409 or 412) be retried. The item is fetched again before each retry, and
+// as an update only sends the fields being changed, sending it again applies
+// the same change on top of the other one.
+func (c *Client) SetRetryOnConflict(retry bool) {
+	c.retryConflicts = retry
+}
+
+// SetDebugOutput enables logging of every HTTP request and response to w.
+// The bearer token is always redacted from the log.
+func (c *Client) SetDebugOutput(w io.Writer) {
+	c.debug = w
+}
+
+// doRequest sends a request, serving GETs from the cache while fresh. An
+// expired entry with an ETag or Last-Modified is revalidated with a
+// conditional request, and its body reused if the API answers 304.
 func (c *Client) doRequest(method, endpoint string, body io.Reader) ([]byte, error) {
-	reqURL := baseURL + endpoint
+	var cached *cacheEntry
+	if c.cache != nil && method == "GET" {
+		entry, fresh := c.cache.get(c.cacheScope(), endpoint)
+		if fresh {
+			c.logf("> %s %s (cached)\n", method, c.baseURL+endpoint)
+			return entry.Body, nil
+		}
+		if entry != nil && entry.canRevalidate() {
+			cached = entry
+		}
+	}
+
+	var header http.Header
+	if cached != nil {
+		header = cached.conditionalHeaders()
+	}
+	respBody, respHeader, err := c.doRawRequest(method, endpoint, body, header)
+	if cached != nil && errors.Is(err, errNotModified) {
+		c.cache.put(c.cacheScope(), endpoint, cached.Body, cached.ETag, cached.LastModified)
+		return cached.Body, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		if method == "GET" {
+			c.cache.put(c.cacheScope(), endpoint, respBody, respHeader.Get("ETag"), respHeader.Get("Last-Modified"))
+		} else {
+			c.cache.invalidate(endpoint)
+		}
+	}
+
+	return respBody, nil
+}
+
+func (c *Client) doUncachedRequest(method, endpoint string, body io.Reader) ([]byte, error) {
+	respBody, _, err := c.doRawRequest(method, endpoint, body, nil)
+	return respBody, err
+}
+
+// doRawRequest sends a request with extra headers, bypassing the cache,
+// and returns the response body and headers
+func (c *Client) doRawRequest(method, endpoint string, body io.Reader, header http.Header) ([]byte, http.Header, error) {
+	reqURL := c.baseURL + endpoint
+
+	var reqBody []byte
+	if body != nil {
+		var err error
+		reqBody, err = io.ReadAll(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading request body: %w", err)
+		}
+		body = bytes.NewReader(reqBody)
+	}
 
-	req, err := http.NewRequest(method, reqURL, body)
+	req, err := http.NewRequestWithContext(c.context(), method, reqURL, body)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, nil, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+c.token)
@@ -49,27 +215,207 @@ func (c *Client) doRequest(method, endpoint string, body io.Reader) ([]byte, err
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	return c.sendRequest(req, string(reqBody))
+}
+
+// maxRetries is how often a rate-limited (429) or, with SetRetryOnConflict,
+// conflicting request is retried
+const maxRetries = 3
+
+// conflictBackoff is the wait before the first retry of a conflicting
+// update; it grows with each attempt
+const conflictBackoff = 500 * time.Millisecond
+
+// send executes a prepared request and returns the response body
+func (c *Client) send(req *http.Request, logBody string) ([]byte, error) {
+	respBody, _, err := c.sendRequest(req, logBody)
+	return respBody, err
+}
+
+// sendRequest executes a prepared request and returns the response body
+// and headers, and records requests that change something in the audit
+// log, if enabled
+func (c *Client) sendRequest(req *http.Request, logBody string) ([]byte, http.Header, error) {
+	respBody, header, err := c.sendWithRetries(req, logBody)
+	if c.audit != nil && req.Method != http.MethodGet {
+		c.audit.record(c, req, respBody, err)
+	}
+	return respBody, header, err
+}
+
+// sendWithRetries executes a prepared request and returns the response body,
+// converting error responses into errors. Rate-limited requests are
+// retried after the delay the API asks for, and conflicting updates after
+// a short backoff and a fresh GET of the item if SetRetryOnConflict is on.
+// logBody is what gets logged for the request body in debug mode.
+func (c *Client) sendWithRetries(req *http.Request, logBody string) ([]byte, http.Header, error) {
+	for attempt := 0; ; attempt++ {
+		respBody, header, retryAfter, err := c.sendOnce(req, logBody)
+		reason := "rate limited"
+		if c.retryConflicts && req.Method == http.MethodPut && errors.Is(err, ErrConflict) {
+			retryAfter = time.Duration(attempt+1) * conflictBackoff
+			reason = "conflict"
+		}
+		if retryAfter == 0 || attempt == maxRetries || req.GetBody == nil && req.Body != nil {
+			return respBody, header, err
+		}
+
+		c.logf("< %s, retrying in %s\n", reason, retryAfter)
+		select {
+		case <-time.After(retryAfter):
+		case <-req.Context().Done():
+			return nil, nil, req.Context().Err()
+		}
+
+		if reason == "conflict" {
+			if err := c.refetch(req); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, nil, fmt.Errorf("rewinding request body: %w", err)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// refetch reads the item a conflicting update was sent to, so that the
+// retry is made against its current state
+func (c *Client) refetch(req *http.Request) error {
+	get, err := http.NewRequestWithContext(req.Context(), http.MethodGet, req.URL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	get.Header.Set("Authorization", req.Header.Get("Authorization"))
+	get.Header.Set("Accept", "application/json")
+
+	_, _, _, err = c.sendOnce(get, "")
+	return err
+}
+
+// sendOnce executes req a single time. For 429 responses it also returns
+// how long to wait before retrying, and for 304 errNotModified.
+func (c *Client) sendOnce(req *http.Request, logBody string) ([]byte, http.Header, time.Duration, error) {
+	release, err := c.limiter.acquire(req.Context())
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer release()
+
+	c.logRequest(req, logBody)
+	start := time.Now()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+		c.logf("< error: %v\n", err)
+		return nil, nil, 0, fmt.Errorf("executing request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+		return nil, nil, 0, fmt.Errorf("reading response: %w", err)
+	}
+
+	c.logResponse(resp, respBody, time.Since(start))
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp.Header, 0, errNotModified
 	}
 
 	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: string(respBody)}
 		var errResp ErrorResponse
 		if err := json.Unmarshal(respBody, &errResp); err == nil && len(errResp.Errors) > 0 {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Errors[0].Message)
+			apiErr.Message = errResp.Errors[0].Message
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+
+		var retryAfter time.Duration
+		if resp.StatusCode == http.StatusTooManyRequests {
+			retryAfter = time.Second
+			if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+				retryAfter = time.Duration(secs) * time.Second
+			}
+			// The limit applies to the token, so every request waits
+			c.limiter.pause(retryAfter)
+		}
+		return nil, resp.Header, retryAfter, apiErr
 	}
 
-	return respBody, nil
+	return respBody, resp.Header, 0, nil
+}
+
+// maxLoggedBody limits how much of a response body is written in debug mode
+const maxLoggedBody = 2000
+
+func (c *Client) logf(format string, args ...interface{}) {
+	if c.debug == nil {
+		return
+	}
+	fmt.Fprint(c.debug, c.redact(fmt.Sprintf(format, args...)))
+}
+
+// logRequest and logResponse write each message in a single call so that
+// output from concurrent requests doesn't interleave.
+func (c *Client) logRequest(req *http.Request, body string) {
+	if c.debug == nil {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "> %s %s\n", req.Method, req.URL.String())
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "Authorization" {
+			value = "Bearer [REDACTED]"
+		}
+		fmt.Fprintf(&sb, "> %s: %s\n", name, value)
+	}
+
+	if body != "" {
+		fmt.Fprintf(&sb, "> %s\n", body)
+	}
+	c.logf("%s", sb.String())
+}
+
+func (c *Client) logResponse(resp *http.Response, body []byte, elapsed time.Duration) {
+	if c.debug == nil {
+		return
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "< %s %s (%s)\n", resp.Status, resp.Request.URL.Path, elapsed.Round(time.Millisecond))
+
+	logged := string(body)
+	if len(logged) > maxLoggedBody {
+		logged = logged[:maxLoggedBody] + fmt.Sprintf("... (%d bytes truncated)", len(body)-maxLoggedBody)
+	}
+	if logged != "" {
+		fmt.Fprintf(&sb, "< %s\n", logged)
+	}
+	c.logf("%s", sb.String())
+}
+
+// redact removes the access token from text that is about to be logged
+func (c *Client) redact(s string) string {
+	if c.token == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.token, "[REDACTED]")
 }
 
 type ErrorResponse struct {
@@ -87,6 +433,7 @@ type Task struct {
 	HTMLNotes    string   `json:"html_notes,omitempty"`
 	Completed    bool     `json:"completed"`
 	CompletedAt  string   `json:"completed_at,omitempty"`
+	StartOn      string   `json:"start_on,omitempty"`
 	DueOn        string   `json:"due_on,omitempty"`
 	DueAt        string   `json:"due_at,omitempty"`
 	CreatedAt    string   `json:"created_at,omitempty"`
@@ -94,13 +441,72 @@ type Task struct {
 	Assignee     *User    `json:"assignee,omitempty"`
 	Projects     []Entity `json:"projects,omitempty"`
 	Tags         []Entity `json:"tags,omitempty"`
+	Parent       *Entity  `json:"parent,omitempty"`
 	Permalink    string   `json:"permalink_url,omitempty"`
+	Liked        bool     `json:"liked,omitempty"`
+	NumLikes     int      `json:"num_likes,omitempty"`
+	NumSubtasks  int      `json:"num_subtasks,omitempty"`
+
+	// ActualTimeMinutes is the total time tracked on the task. It's read-only;
+	// time is added through time-tracking entries.
+	ActualTimeMinutes float64 `json:"actual_time_minutes,omitempty"`
+
+	// AssigneeSection is the assignee's My Tasks section (Today, Upcoming, ...)
+	AssigneeSection *Entity `json:"assignee_section,omitempty"`
+
+	// Memberships lists the projects the task is in and its section in each
+	Memberships []Membership `json:"memberships,omitempty"`
+
+	CustomFields []CustomField `json:"custom_fields,omitempty"`
+
+	// Extra holds fields the API returned that Task doesn't model, e.g.
+	// ones requested with WithOptFields
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (t *Task) UnmarshalJSON(data []byte) error {
+	type plain Task
+	return unmarshalWithExtra(data, (*plain)(t), &t.Extra)
+}
+
+func (t Task) MarshalJSON() ([]byte, error) {
+	type plain Task
+	return marshalWithExtra(plain(t), t.Extra)
+}
+
+// CustomField represents the value of a custom field on a task
+type CustomField struct {
+	GID             string `json:"gid"`
+	Name            string `json:"name"`
+	ResourceSubtype string `json:"resource_subtype,omitempty"`
+	DisplayValue    string `json:"display_value,omitempty"`
+
+	// For enum fields: the chosen option, and all options in their order
+	EnumValue   *EnumOption  `json:"enum_value,omitempty"`
+	EnumOptions []EnumOption `json:"enum_options,omitempty"`
+}
+
+// EnumOption is one of the choices of an enum custom field
+type EnumOption struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+// Membership is a task's placement in one project
+type Membership struct {
+	Project *Entity `json:"project,omitempty"`
+	Section *Entity `json:"section,omitempty"`
 }
 
 type User struct {
 	GID   string `json:"gid"`
 	Name  string `json:"name,omitempty"`
 	Email string `json:"email,omitempty"`
+
+	// Photo maps sizes (image_21x21 ... image_1024x1024) to URLs and
+	// Workspaces lists the user's workspaces; both only set by GetUser
+	Photo      map[string]string `json:"photo,omitempty"`
+	Workspaces []Entity          `json:"workspaces,omitempty"`
 }
 
 type Entity struct {
@@ -115,6 +521,21 @@ type Project struct {
 	Color     string `json:"color,omitempty"`
 	CreatedAt string `json:"created_at,omitempty"`
 	Permalink string `json:"permalink_url,omitempty"`
+	Notes     string `json:"notes,omitempty"`
+	Owner     *User  `json:"owner,omitempty"`
+
+	// Extra holds fields the API returned that Project doesn't model
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type plain Project
+	return unmarshalWithExtra(data, (*plain)(p), &p.Extra)
+}
+
+func (p Project) MarshalJSON() ([]byte, error) {
+	type plain Project
+	return marshalWithExtra(plain(p), p.Extra)
 }
 
 type Story struct {
@@ -124,6 +545,13 @@ type Story struct {
 	Text      string `json:"text,omitempty"`
 	HTMLText  string `json:"html_text,omitempty"`
 	Type      string `json:"type,omitempty"`
+
+	// ResourceSubtype tells comments ("comment_added") apart from the
+	// various kinds of system activity
+	ResourceSubtype string `json:"resource_subtype,omitempty"`
+
+	// IsPinned is set for comments pinned to the top of the task
+	IsPinned bool `json:"is_pinned,omitempty"`
 }
 
 type TasksResponse struct {
@@ -154,22 +582,124 @@ type Page struct {
 	URI    string `json:"uri"`
 }
 
+// errStopPaging can be returned from an eachPage callback to stop
+// iterating without reporting an error
+var errStopPaging = errors.New("stop paging")
+
+// eachPage requests a collection endpoint and follows next_page offsets,
+// calling fn with the raw data of every page. Iteration stops at the last
+// page or when fn returns an error.
+func (c *Client) eachPage(endpoint string, params url.Values, fn func(data json.RawMessage) error) error {
+	for {
+		body, err := c.doRequest("GET", endpoint+"?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+
+		var resp struct {
+			Data     json.RawMessage `json:"data"`
+			NextPage *Page           `json:"next_page,omitempty"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+
+		if err := fn(resp.Data); err != nil {
+			if errors.Is(err, errStopPaging) {
+				return nil
+			}
+			return err
+		}
+
+		if resp.NextPage == nil || resp.NextPage.Offset == "" {
+			return nil
+		}
+		params.Set("offset", resp.NextPage.Offset)
+	}
+}
+
+// maxPageSize is the largest page the API returns
+const maxPageSize = 100
+
+// errOffsetLimit is returned when an offset is combined with a limit that
+// would need more than one page
+var errOffsetLimit = fmt.Errorf("an offset resumes a single page, so it needs a limit of 1 to %d", maxPageSize)
+
+// collectPages follows the pages of a collection endpoint and returns up
+// to limit items. A limit of 0 or less returns every item.
+func collectPages[T any](c *Client, endpoint string, params url.Values, limit int) ([]T, error) {
+	pageSize := maxPageSize
+	if limit > 0 {
+		pageSize = min(limit, maxPageSize)
+	}
+	params.Set("limit", fmt.Sprintf("%d", pageSize))
+
+	var items []T
+	err := c.eachPage(endpoint, params, func(data json.RawMessage) error {
+		var page []T
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		items = append(items, page...)
+		if limit > 0 && len(items) >= limit {
+			items = items[:limit]
+			return errStopPaging
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
 // TaskListOptions contains all filtering options for listing tasks
 type TaskListOptions struct {
 	Project          string // Project GID
+	Text             string // Full-text query matched against names and descriptions
 	Assignee         string // Assignee GID or "me"
+	Unassigned       bool   // Only tasks without an assignee
+	Assigned         bool   // Only tasks with an assignee
 	Tag              string // Tag GID
 	Due              string // Due filter: today, tomorrow, week, overdue, or YYYY-MM-DD
+	OverdueDays      int    // Only tasks overdue by more than this many days
+	DueAfter         string // Only tasks due after this date (YYYY-MM-DD)
+	DueBefore        string // Only tasks due before this date (YYYY-MM-DD)
+	ModifiedAfter    string // Only tasks modified after this date (YYYY-MM-DD) or RFC 3339 time
+	CreatedAfter     string // Only tasks created after this date (YYYY-MM-DD) or RFC 3339 time
+	CompletedAfter   string // Only tasks completed after this date (YYYY-MM-DD) or RFC 3339 time
 	IncludeCompleted bool   // Include completed tasks
+	CompletedOnly    bool   // Only completed tasks
+	IncludeSubtasks  bool   // Include subtasks (fetched with their parent)
 	Limit            int    // Maximum results
-	SortBy           string // Sort field: due_date, created_at, modified_at
+	SortBy           string // Sort field: due_date, created_at, modified_at, completed_at, likes
+	SortDescending   bool   // Sort in descending order
+	OptFields        string // Fields to fetch (comma-separated); empty uses the default set
+
+	// OnPage, if set, receives the tasks page by page as they are fetched
+	// instead of ListTasks collecting them, so memory stays bounded. Tasks
+	// spanning several requests then arrive newest-created first rather
+	// than in SortBy order.
+	OnPage func([]Task) error
 }
 
 // ListTasks returns tasks filtered by the given options
 func (c *Client) ListTasks(opts TaskListOptions) ([]Task, error) {
-	// Use the search API for advanced filtering
+	params, optFields := c.taskSearchParams(opts)
+	return c.search(params, optFields, opts.Limit, opts.SortBy, opts.SortDescending, opts.OnPage)
+}
+
+// taskSearchParams turns list options into search API parameters and the
+// opt_fields to request. ListTasks and SearchTasks share it so their
+// filters behave the same.
+func (c *Client) taskSearchParams(opts TaskListOptions) (url.Values, string) {
 	params := url.Values{}
 
+	if opts.Text != "" {
+		params.Set("text", opts.Text)
+	}
+
 	// Project filter
 	if opts.Project != "" {
 		params.Set("projects.any", opts.Project)
@@ -179,6 +709,13 @@ func (c *Client) ListTasks(opts TaskListOptions) ([]Task, error) {
 	if opts.Assignee != "" {
 		params.Set("assignee.any", opts.Assignee)
 	}
+	// Filtering on the server keeps --limit counting matching tasks only
+	switch {
+	case opts.Unassigned:
+		params.Set("assignee.any", "null")
+	case opts.Assigned:
+		params.Set("assignee.not", "null")
+	}
 
 	// Tag filter
 	if opts.Tag != "" {
@@ -189,42 +726,215 @@ func (c *Client) ListTasks(opts TaskListOptions) ([]Task, error) {
 	if opts.Due != "" {
 		c.applyDueFilter(params, opts.Due)
 	}
+	if opts.OverdueDays > 0 {
+		params.Set("due_on.before", time.Now().AddDate(0, 0, -opts.OverdueDays).Format("2006-01-02"))
+	}
+	if opts.DueAfter != "" {
+		params.Set("due_on.after", opts.DueAfter)
+	}
+	if opts.DueBefore != "" {
+		params.Set("due_on.before", opts.DueBefore)
+	}
 
-	// Completed filter
-	if !opts.IncludeCompleted {
+	// Recency filters
+	setAfterFilter(params, "modified", opts.ModifiedAfter)
+	setAfterFilter(params, "created", opts.CreatedAfter)
+	setAfterFilter(params, "completed", opts.CompletedAfter)
+
+	// Completed filter: incomplete tasks unless asked otherwise
+	switch {
+	case opts.CompletedOnly:
+		params.Set("completed", "true")
+	case !opts.IncludeCompleted && opts.CompletedAfter == "":
 		params.Set("completed", "false")
 	}
 
-	// Limit
-	if opts.Limit > 0 {
-		params.Set("limit", fmt.Sprintf("%d", opts.Limit))
+	optFields := opts.OptFields
+	if optFields == "" {
+		optFields = TaskListOptFields
+	}
+
+	// Subtasks are excluded for cleaner output unless asked for, and then
+	// come with their parent so they can be told apart
+	if opts.IncludeSubtasks {
+		optFields += ",parent,parent.name"
 	} else {
-		params.Set("limit", "100")
+		params.Set("is_subtask", "false")
 	}
+	optFields = c.fields(optFields)
+
+	return params, optFields
+}
+
+// search runs a task search. The search API has no pages and returns at
+// most 100 tasks, so for a limit of 0 (everything) or above 100 it is
+// called repeatedly, walking back in time with created_at.before, and the
+// combined results are sorted locally. Unless the order is newest-created
+// first, which is the order of the walk, a limit above 100 still walks back
+// through every match so that the sort picks the right tasks. If onPage
+// is set, each batch of tasks is passed to it as it arrives and no tasks
+// are returned; a limit above 100 then can't be combined with a sort.
+func (c *Client) search(params url.Values, optFields string, limit int, sortBy string, sortDesc bool, onPage func([]Task) error) ([]Task, error) {
+	endpoint := fmt.Sprintf("/workspaces/%s/tasks/search", c.workspace)
+
+	if limit > 0 && limit <= maxPageSize {
+		params.Set("limit", fmt.Sprintf("%d", limit))
+		if sortBy != "" {
+			params.Set("sort_by", sortBy)
+			params.Set("sort_ascending", fmt.Sprintf("%t", !sortDesc))
+		}
+		params.Set("opt_fields", optFields)
+
+		body, err := c.doRequest("GET", endpoint+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
 
-	// Sort
-	if opts.SortBy != "" {
-		params.Set("sort_by", opts.SortBy)
-		params.Set("sort_ascending", "true")
+		var resp TasksResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+
+		if onPage != nil {
+			return nil, onPage(resp.Data)
+		}
+		return resp.Data, nil
 	}
 
-	// Exclude subtasks for cleaner output
-	params.Set("is_subtask", "false")
+	localSort := sortBy != "" && !(sortBy == "created_at" && sortDesc)
+	if onPage != nil && localSort && limit > 0 {
+		return nil, fmt.Errorf("a limit above %d with a sort needs every task before the first can be written; use a limit of 0 (everything) or at most %d", maxPageSize, maxPageSize)
+	}
+	// The first tasks of the walk are only the first by sort when sorting
+	// newest-created first
+	stopAt := limit
+	if localSort {
+		stopAt = 0
+	}
 
-	params.Set("opt_fields", "gid,name,completed,due_on,assignee,assignee.name,projects,projects.name,tags,tags.name,permalink_url")
+	params.Set("limit", fmt.Sprintf("%d", maxPageSize))
+	params.Set("sort_by", "created_at")
+	params.Set("sort_ascending", "false")
+	params.Set("opt_fields", optFields+",created_at"+sortFields[sortBy])
 
-	endpoint := fmt.Sprintf("/workspaces/%s/tasks/search?%s", c.workspace, params.Encode())
-	body, err := c.doRequest("GET", endpoint, nil)
+	var tasks []Task
+	seen := map[string]bool{}
+	count := 0
+	for {
+		body, err := c.doRequest("GET", endpoint+"?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var resp TasksResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return nil, fmt.Errorf("parsing response: %w", err)
+		}
+
+		var page []Task
+		for _, t := range resp.Data {
+			if !seen[t.GID] {
+				seen[t.GID] = true
+				page = append(page, t)
+			}
+		}
+		if onPage != nil {
+			if stopAt > 0 && count+len(page) > stopAt {
+				page = page[:stopAt-count]
+			}
+			if err := onPage(page); err != nil {
+				return nil, err
+			}
+		} else {
+			tasks = append(tasks, page...)
+		}
+		count += len(page)
+
+		if len(resp.Data) < maxPageSize || len(page) == 0 || (stopAt > 0 && count >= stopAt) {
+			break
+		}
+		// created_at.before is exclusive, so move it just past the last
+		// task; tasks created in the same millisecond come back again and
+		// are skipped as seen, rather than being missed
+		before := inclusiveBefore(resp.Data[len(resp.Data)-1].CreatedAt)
+		if before == "" || before == params.Get("created_at.before") {
+			break
+		}
+		params.Set("created_at.before", before)
+	}
+
+	if localSort {
+		sortTasks(tasks, sortBy, sortDesc)
+	}
+	if limit > 0 && len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nil
+}
+
+// inclusiveBefore returns a created_at.before value that still matches a
+// task created at t, or "" if t isn't a timestamp
+func inclusiveBefore(t string) string {
+	created, err := time.Parse(time.RFC3339Nano, t)
 	if err != nil {
-		return nil, err
+		return ""
 	}
+	return created.Add(time.Millisecond).UTC().Format("2006-01-02T15:04:05.000Z")
+}
 
-	var resp TasksResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
+// sortFields are the extra opt_fields needed to sort tasks locally
+var sortFields = map[string]string{
+	"due_date":     ",due_on,due_at",
+	"modified_at":  ",modified_at",
+	"completed_at": ",completed_at",
+	"likes":        ",num_likes",
+}
+
+// sortTasks sorts tasks locally the way the search API's sort_by would.
+// Tasks without a value for the field go last.
+func sortTasks(tasks []Task, sortBy string, desc bool) {
+	key := func(t Task) string {
+		switch sortBy {
+		case "due_date":
+			if t.DueAt != "" {
+				return t.DueAt
+			}
+			return t.DueOn
+		case "created_at":
+			return t.CreatedAt
+		case "modified_at":
+			return t.ModifiedAt
+		case "completed_at":
+			return t.CompletedAt
+		case "likes":
+			return fmt.Sprintf("%010d", t.NumLikes)
+		}
+		return ""
 	}
 
-	return resp.Data, nil
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := key(tasks[i]), key(tasks[j])
+		if a == "" || b == "" {
+			return a != "" && b == ""
+		}
+		if desc {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+// setAfterFilter adds a <field>_on.after filter for dates or a
+// <field>_at.after filter for full timestamps
+func setAfterFilter(params url.Values, field, value string) {
+	switch {
+	case value == "":
+	case len(value) == len("2006-01-02"):
+		params.Set(field+"_on.after", value)
+	default:
+		params.Set(field+"_at.after", value)
+	}
 }
 
 // applyDueFilter adds due date parameters based on the filter string
@@ -249,45 +959,17 @@ func (c *Client) applyDueFilter(params url.Values, due string) {
 	}
 }
 
-// SearchTasks searches for tasks in the workspace
-func (c *Client) SearchTasks(query string, limit int) ([]Task, error) {
-	params := url.Values{}
-
-	if query != "" {
-		params.Set("text", query)
-	}
-
-	params.Set("completed", "false")
-
-	if limit > 0 {
-		params.Set("limit", fmt.Sprintf("%d", limit))
-	} else {
-		params.Set("limit", "100")
-	}
-
-	params.Set("opt_fields", "gid,name,completed,due_on,assignee,assignee.name,projects,projects.name,permalink_url")
-
-	endpoint := fmt.Sprintf("/workspaces/%s/tasks/search?%s", c.workspace, params.Encode())
-	body, err := c.doRequest("GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var resp TasksResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
-	}
-
-	return resp.Data, nil
+// SearchTasks runs a full-text search for query in the workspace, narrowed
+// by the same filters as ListTasks. As with ListTasks, completed tasks are
+// left out unless opts asks for them.
+func (c *Client) SearchTasks(query string, opts TaskListOptions) ([]Task, error) {
+	opts.Text = query
+	return c.ListTasks(opts)
 }
 
 // GetTask returns a single task by GID
 func (c *Client) GetTask(gid string) (*Task, error) {
-	params := url.Values{}
-	params.Set("opt_fields", "gid,name,notes,html_notes,completed,completed_at,due_on,due_at,created_at,modified_at,assignee,assignee.name,assignee.email,projects,projects.name,tags,tags.name,permalink_url")
-
-	endpoint := fmt.Sprintf("/tasks/%s?%s", gid, params.Encode())
-	body, err := c.doRequest("GET", endpoint, nil)
+	body, err := c.GetTaskRaw(gid, "")
 	if err != nil {
 		return nil, err
 	}
@@ -300,8 +982,242 @@ func (c *Client) GetTask(gid string) (*Task, error) {
 	return &resp.Data, nil
 }
 
-// AddComment adds a comment (story) to a task
-// The comment can be plain text or HTML for rich text formatting
+// TaskListOptFields are the fields task listings fetch unless
+// TaskListOptions.OptFields says otherwise
+const TaskListOptFields = "gid,name,completed,due_on,assignee,assignee.name,projects,projects.name,tags,tags.name,permalink_url"
+
+// taskOptFields are the fields GetTask fetches
+const taskOptFields = "gid,name,notes,html_notes,completed,completed_at,start_on,due_on,due_at,created_at,modified_at,assignee,assignee.name,assignee.email,projects,projects.name,tags,tags.name,permalink_url,liked,num_likes,num_subtasks,actual_time_minutes"
+
+// GetTaskRaw returns the API's JSON response for a task unchanged, including
+// fields the Task struct doesn't model. optFields selects the fields to
+// fetch; empty uses the same set as GetTask.
+func (c *Client) GetTaskRaw(gid, optFields string) ([]byte, error) {
+	if optFields == "" {
+		optFields = taskOptFields
+	}
+
+	params := url.Values{}
+	params.Set("opt_fields", c.fields(optFields))
+
+	endpoint := fmt.Sprintf("/tasks/%s?%s", gid, params.Encode())
+	return c.doRequest("GET", endpoint, nil)
+}
+
+// EachProjectTask calls fn for every task in a project, including completed
+// tasks, following pagination so that only one page is held in memory
+func (c *Client) EachProjectTask(projectGID string, fn func(Task) error) error {
+	params := url.Values{}
+	params.Set("limit", "100")
+	params.Set("opt_fields", "gid,name,notes,completed,completed_at,due_on,due_at,created_at,modified_at,assignee,assignee.name,assignee.email,projects,projects.name,tags,tags.name,permalink_url,custom_fields,custom_fields.name,custom_fields.resource_subtype,custom_fields.display_value")
+
+	endpoint := fmt.Sprintf("/projects/%s/tasks", projectGID)
+	return c.eachPage(endpoint, params, func(data json.RawMessage) error {
+		var tasks []Task
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetProjectTasks returns up to limit tasks (0 for all) of a project in the
+// project's own order. Completed tasks are only included when includeCompleted is
+// set. optFields selects the fields to fetch; empty uses the default set.
+func (c *Client) GetProjectTasks(projectGID string, includeCompleted bool, limit int, optFields string) ([]Task, error) {
+	if optFields == "" {
+		optFields = "gid,name,completed,due_on,assignee,assignee.name,projects,projects.name,tags,tags.name,permalink_url"
+	}
+
+	params := url.Values{}
+	params.Set("opt_fields", c.fields(optFields))
+	if !includeCompleted {
+		params.Set("completed_since", "now")
+	}
+
+	endpoint := fmt.Sprintf("/projects/%s/tasks", projectGID)
+	return collectPages[Task](c, endpoint, params, limit)
+}
+
+// GetTagTasks returns up to limit tasks (0 for all) with a tag, in the
+// tag's own order. The endpoint has no completed filter, so completed
+// tasks are skipped here unless includeCompleted is set.
+func (c *Client) GetTagTasks(tagGID string, includeCompleted bool, limit int, optFields string) ([]Task, error) {
+	if optFields == "" {
+		optFields = "gid,name,completed,due_on,assignee,assignee.name,projects,projects.name,tags,tags.name,permalink_url"
+	}
+	optFields = c.fields(optFields)
+
+	if !strings.Contains(","+optFields+",", ",completed,") {
+		optFields += ",completed"
+	}
+
+	params := url.Values{}
+	params.Set("opt_fields", optFields)
+	params.Set("limit", fmt.Sprintf("%d", maxPageSize))
+
+	var tasks []Task
+	endpoint := fmt.Sprintf("/tags/%s/tasks", tagGID)
+	err := c.eachPage(endpoint, params, func(data json.RawMessage) error {
+		var page []Task
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		for _, t := range page {
+			if t.Completed && !includeCompleted {
+				continue
+			}
+			tasks = append(tasks, t)
+			if limit > 0 && len(tasks) >= limit {
+				return errStopPaging
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// Section is a section (board column) of a project
+type Section struct {
+	GID     string  `json:"gid"`
+	Name    string  `json:"name"`
+	Project *Entity `json:"project,omitempty"`
+}
+
+type SectionResponse struct {
+	Data Section `json:"data"`
+}
+
+// GetSection returns a section and the project it belongs to
+func (c *Client) GetSection(gid string) (*Section, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,project,project.name")
+
+	endpoint := fmt.Sprintf("/sections/%s?%s", gid, params.Encode())
+	body, err := c.doRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SectionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// AddTaskToSection moves a task into a section. The task is placed just
+// before insertBefore or just after insertAfter (task GIDs in the same
+// section); with neither it goes to the end of the section.
+func (c *Client) AddTaskToSection(sectionGID, taskGID, insertBefore, insertAfter string) error {
+	data := map[string]interface{}{
+		"task": taskGID,
+	}
+	if insertBefore != "" {
+		data["insert_before"] = insertBefore
+	}
+	if insertAfter != "" {
+		data["insert_after"] = insertAfter
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/sections/%s/addTask", sectionGID)
+	_, err = c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	return err
+}
+
+// ListSections returns the sections of a project in project order
+func (c *Client) ListSections(projectGID string) ([]Section, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name")
+	params.Set("limit", "100")
+
+	var sections []Section
+	endpoint := fmt.Sprintf("/projects/%s/sections", projectGID)
+	err := c.eachPage(endpoint, params, func(data json.RawMessage) error {
+		var page []Section
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		sections = append(sections, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sections, nil
+}
+
+// UpdateSection renames a section
+func (c *Client) UpdateSection(sectionGID, name string) (*Section, error) {
+	payload := map[string]interface{}{
+		"data": map[string]interface{}{
+			"name": name,
+		},
+	}
+
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/sections/%s", sectionGID)
+	body, err := c.doRequest("PUT", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp SectionResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// InsertSection moves a section of a project to just before beforeGID or
+// just after afterGID (sections of the same project); exactly one must be
+// given.
+func (c *Client) InsertSection(projectGID, sectionGID, beforeGID, afterGID string) error {
+	data := map[string]interface{}{
+		"section": sectionGID,
+	}
+	if beforeGID != "" {
+		data["before_section"] = beforeGID
+	}
+	if afterGID != "" {
+		data["after_section"] = afterGID
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/projects/%s/sections/insert", projectGID)
+	_, err = c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	return err
+}
+
+// AddComment adds a comment (story) to a task
+// The comment can be plain text or HTML for rich text formatting
 // For rich text, wrap content in <body> tags and use supported HTML:
 // <strong>, <em>, <u>, <s>, <code>, <ol>, <ul>, <li>, <a>, <blockquote>, <pre>
 func (c *Client) AddComment(taskGID, comment string, isHTML bool) (*Story, error) {
@@ -334,25 +1250,865 @@ func (c *Client) AddComment(taskGID, comment string, isHTML bool) (*Story, error
 	return &resp.Data, nil
 }
 
-// DeleteStory deletes a comment (story) from a task
-func (c *Client) DeleteStory(storyGID string) error {
-	endpoint := fmt.Sprintf("/stories/%s", storyGID)
-	_, err := c.doRequest("DELETE", endpoint, nil)
-	return err
+// AddTimeTrackingEntry records minutes of time spent on a task on the given
+// date (YYYY-MM-DD), adding to its actual_time_minutes
+func (c *Client) AddTimeTrackingEntry(taskGID string, minutes int, enteredOn string) error {
+	payload := map[string]interface{}{
+		"data": map[string]interface{}{
+			"duration_minutes": minutes,
+			"entered_on":       enteredOn,
+		},
+	}
+
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/tasks/%s/time_tracking_entries", taskGID)
+	_, err = c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	return err
+}
+
+// SetStoryPinned pins a comment to the top of its task, or unpins it
+func (c *Client) SetStoryPinned(storyGID string, pinned bool) (*Story, error) {
+	payload := map[string]interface{}{
+		"data": map[string]interface{}{
+			"is_pinned": pinned,
+		},
+	}
+
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/stories/%s", storyGID)
+	body, err := c.doRequest("PUT", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp StoryResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// DeleteStory deletes a comment (story) from a task
+func (c *Client) DeleteStory(storyGID string) error {
+	endpoint := fmt.Sprintf("/stories/%s", storyGID)
+	_, err := c.doRequest("DELETE", endpoint, nil)
+	return err
+}
+
+// GetTaskStories returns all stories (comments and activity) for a task,
+// following pagination. With commentsOnly, system activity is left out; the
+// API can't filter stories, so this is done after fetching.
+func (c *Client) GetTaskStories(taskGID string, commentsOnly bool) ([]Story, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,created_at,created_by,created_by.name,text,html_text,type,resource_subtype,is_pinned")
+
+	endpoint := fmt.Sprintf("/tasks/%s/stories", taskGID)
+	stories, err := collectPages[Story](c, endpoint, params, 0)
+	if err != nil || !commentsOnly {
+		return stories, err
+	}
+
+	comments := stories[:0]
+	for _, s := range stories {
+		if s.ResourceSubtype == "comment_added" {
+			comments = append(comments, s)
+		}
+	}
+	return comments, nil
+}
+
+// ArchivedFilter selects projects by whether they are archived
+type ArchivedFilter int
+
+const (
+	ActiveProjects   ArchivedFilter = iota // only projects that aren't archived
+	AllProjects                            // archived and active projects
+	ArchivedProjects                       // only archived projects
+)
+
+// ListProjects returns up to limit projects (0 for all) in the workspace,
+// and the next page if there are more results. optFields selects the
+// fields to fetch; empty uses the default set. offset continues from a
+// previous next page.
+func (c *Client) ListProjects(archived ArchivedFilter, limit int, optFields, offset string) ([]Project, *Page, error) {
+	return c.listProjects(fmt.Sprintf("/workspaces/%s/projects", c.workspace), archived, limit, optFields, offset)
+}
+
+// ListTeamProjects is like ListProjects, but returns only a team's projects
+func (c *Client) ListTeamProjects(teamGID string, archived ArchivedFilter, limit int, optFields, offset string) ([]Project, *Page, error) {
+	return c.listProjects(fmt.Sprintf("/teams/%s/projects", teamGID), archived, limit, optFields, offset)
+}
+
+func (c *Client) listProjects(endpoint string, archived ArchivedFilter, limit int, optFields, offset string) ([]Project, *Page, error) {
+	// The API's archived parameter selects one or the other; leaving it out
+	// returns both
+	params := url.Values{}
+	switch archived {
+	case ActiveProjects:
+		params.Set("archived", "false")
+	case ArchivedProjects:
+		params.Set("archived", "true")
+	}
+
+	if offset != "" {
+		if limit <= 0 || limit > maxPageSize {
+			return nil, nil, errOffsetLimit
+		}
+		params.Set("offset", offset)
+	}
+
+	if optFields == "" {
+		optFields = "gid,name,archived,color,created_at,permalink_url"
+	}
+	params.Set("opt_fields", c.fields(optFields))
+
+	// More than one page: follow the pages and return everything at once
+	if limit <= 0 || limit > maxPageSize {
+		projects, err := collectPages[Project](c, endpoint, params, limit)
+		return projects, nil, err
+	}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+
+	body, err := c.doRequest("GET", endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resp ProjectsResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return resp.Data, resp.NextPage, nil
+}
+
+type ProjectResponse struct {
+	Data Project `json:"data"`
+}
+
+// GetProject returns a single project by GID
+func (c *Client) GetProject(gid string) (*Project, error) {
+	params := url.Values{}
+	params.Set("opt_fields", c.fields("gid,name,notes,archived,color,created_at,permalink_url,owner,owner.name"))
+
+	endpoint := fmt.Sprintf("/projects/%s?%s", gid, params.Encode())
+	body, err := c.doRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ProjectResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// ProjectStatus is a status update posted on a project
+type ProjectStatus struct {
+	GID       string `json:"gid"`
+	Title     string `json:"title,omitempty"`
+	Text      string `json:"text,omitempty"`
+	Color     string `json:"color,omitempty"`
+	CreatedAt string `json:"created_at,omitempty"`
+	CreatedBy *User  `json:"created_by,omitempty"`
+}
+
+type ProjectStatusResponse struct {
+	Data ProjectStatus `json:"data"`
+}
+
+type ProjectStatusesResponse struct {
+	Data []ProjectStatus `json:"data"`
+}
+
+// CreateProjectStatus posts a status update on a project.
+// color is one of green, yellow or red.
+func (c *Client) CreateProjectStatus(projectGID, title, text, color string) (*ProjectStatus, error) {
+	data := map[string]interface{}{
+		"text":  text,
+		"color": color,
+	}
+	if title != "" {
+		data["title"] = title
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/projects/%s/project_statuses", projectGID)
+	body, err := c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ProjectStatusResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// ListProjectStatuses returns the status updates of a project, newest first
+func (c *Client) ListProjectStatuses(projectGID string, limit int) ([]ProjectStatus, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,title,text,color,created_at,created_by,created_by.name")
+
+	endpoint := fmt.Sprintf("/projects/%s/project_statuses", projectGID)
+	return collectPages[ProjectStatus](c, endpoint, params, limit)
+}
+
+// ProjectMember is one member of a project and their access to it
+type ProjectMember struct {
+	GID         string `json:"gid"`
+	Member      *User  `json:"member,omitempty"`
+	WriteAccess string `json:"write_access,omitempty"`
+}
+
+// GetProjectMembers returns the members of a project
+func (c *Client) GetProjectMembers(projectGID string) ([]ProjectMember, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,member,member.name,member.email,write_access")
+	params.Set("limit", "100")
+
+	var members []ProjectMember
+	endpoint := fmt.Sprintf("/projects/%s/memberships", projectGID)
+	err := c.eachPage(endpoint, params, func(data json.RawMessage) error {
+		var page []ProjectMember
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		members = append(members, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// AddProjectMembers adds users to a project
+func (c *Client) AddProjectMembers(projectGID string, userGIDs []string) (*Project, error) {
+	return c.changeProjectMembers(projectGID, "addMembers", userGIDs)
+}
+
+// RemoveProjectMembers removes users from a project
+func (c *Client) RemoveProjectMembers(projectGID string, userGIDs []string) (*Project, error) {
+	return c.changeProjectMembers(projectGID, "removeMembers", userGIDs)
+}
+
+func (c *Client) changeProjectMembers(projectGID, action string, userGIDs []string) (*Project, error) {
+	payload := map[string]interface{}{
+		"data": map[string]interface{}{
+			"members": strings.Join(userGIDs, ","),
+		},
+	}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/projects/%s/%s", projectGID, action)
+	body, err := c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ProjectResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// CreateTaskOptions contains options for creating a new task
+type CreateTaskOptions struct {
+	Name      string
+	Notes     string
+	HTMLNotes string // Rich text description (HTML)
+	Assignee  string
+	StartOn   string // Needs DueOn
+	DueOn     string
+	Projects  []string
+	Tags      []string
+	Parent    string // For subtasks
+}
+
+// CreateTask creates a new task in the workspace
+func (c *Client) CreateTask(opts CreateTaskOptions) (*Task, error) {
+	data := map[string]interface{}{
+		"name": opts.Name,
+	}
+
+	if opts.HTMLNotes != "" {
+		data["html_notes"] = opts.HTMLNotes
+	} else if opts.Notes != "" {
+		data["notes"] = opts.Notes
+	}
+	if opts.Assignee != "" {
+		data["assignee"] = opts.Assignee
+	}
+	if opts.StartOn != "" {
+		data["start_on"] = opts.StartOn
+	}
+	if opts.DueOn != "" {
+		data["due_on"] = opts.DueOn
+	}
+	if len(opts.Projects) > 0 {
+		data["projects"] = opts.Projects
+	}
+	if len(opts.Tags) > 0 {
+		data["tags"] = opts.Tags
+	}
+	if opts.Parent != "" {
+		data["parent"] = opts.Parent
+	}
+
+	// If no project specified and not a subtask, we need workspace
+	if len(opts.Projects) == 0 && opts.Parent == "" {
+		data["workspace"] = c.workspace
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	body, err := c.doRequest("POST", "/tasks", strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// UpdateTaskOptions contains options for updating a task.
+// A nil field is left unchanged; a pointer to "" clears the field.
+type UpdateTaskOptions struct {
+	Name      *string
+	Notes     *string
+	HTMLNotes *string // Rich text description (HTML)
+	Assignee  *string // Pointer to "" unassigns the task (sends null)
+	DueOn     *string // Pointer to "" removes the due date and time (sends null)
+	DueAt     *string // Due date and time (RFC 3339); sent instead of DueOn when set
+	StartOn   *string // Pointer to "" removes the start date; needs DueOn
+	Completed *bool
+	Liked     *bool
+}
+
+// UpdateTask updates an existing task
+func (c *Client) UpdateTask(taskGID string, opts UpdateTaskOptions) (*Task, error) {
+	data := map[string]interface{}{}
+
+	if opts.Name != nil {
+		data["name"] = *opts.Name
+	}
+	if opts.HTMLNotes != nil {
+		data["html_notes"] = *opts.HTMLNotes
+	} else if opts.Notes != nil {
+		data["notes"] = *opts.Notes
+	}
+	if opts.Assignee != nil {
+		if *opts.Assignee == "" {
+			data["assignee"] = nil
+		} else {
+			data["assignee"] = *opts.Assignee
+		}
+	}
+	if opts.DueAt != nil && *opts.DueAt != "" {
+		data["due_at"] = *opts.DueAt
+	} else if opts.DueOn != nil {
+		if *opts.DueOn == "" {
+			// A task due at a time has due_at set as well, which would
+			// keep the due date
+			data["due_on"] = nil
+			data["due_at"] = nil
+		} else {
+			data["due_on"] = *opts.DueOn
+		}
+	}
+	if opts.StartOn != nil {
+		if *opts.StartOn == "" {
+			data["start_on"] = nil
+		} else {
+			data["start_on"] = *opts.StartOn
+		}
+	}
+	if opts.Completed != nil {
+		data["completed"] = *opts.Completed
+	}
+	if opts.Liked != nil {
+		data["liked"] = *opts.Liked
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/tasks/%s", taskGID)
+	body, err := c.doRequest("PUT", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// ListSubtasks returns the direct subtasks of a task
+func (c *Client) ListSubtasks(taskGID string) ([]Task, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,completed,due_on,assignee,assignee.name")
+
+	endpoint := fmt.Sprintf("/tasks/%s/subtasks?%s", taskGID, params.Encode())
+	body, err := c.doRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TasksResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// CompleteTask marks a task as completed
+func (c *Client) CompleteTask(taskGID string) (*Task, error) {
+	completed := true
+	return c.UpdateTask(taskGID, UpdateTaskOptions{Completed: &completed})
+}
+
+// ReopenTask marks a task as not completed
+func (c *Client) ReopenTask(taskGID string) (*Task, error) {
+	completed := false
+	return c.UpdateTask(taskGID, UpdateTaskOptions{Completed: &completed})
+}
+
+// LikeTask likes a task as the current user
+func (c *Client) LikeTask(taskGID string) (*Task, error) {
+	liked := true
+	return c.UpdateTask(taskGID, UpdateTaskOptions{Liked: &liked})
+}
+
+// UnlikeTask removes the current user's like from a task
+func (c *Client) UnlikeTask(taskGID string) (*Task, error) {
+	liked := false
+	return c.UpdateTask(taskGID, UpdateTaskOptions{Liked: &liked})
+}
+
+// AssignTask assigns a task to a user (GID, email or "me")
+func (c *Client) AssignTask(taskGID, assignee string) (*Task, error) {
+	return c.UpdateTask(taskGID, UpdateTaskOptions{Assignee: &assignee})
+}
+
+// UnassignTask removes the assignee from a task
+func (c *Client) UnassignTask(taskGID string) (*Task, error) {
+	none := ""
+	return c.UpdateTask(taskGID, UpdateTaskOptions{Assignee: &none})
+}
+
+// AddTaskToProject adds a task to a project. If sectionGID is set, the task
+// is placed in that section of the project (moving it there if the task is
+// already in the project).
+func (c *Client) AddTaskToProject(taskGID, projectGID, sectionGID string) error {
+	data := map[string]interface{}{
+		"project": projectGID,
+	}
+	if sectionGID != "" {
+		data["section"] = sectionGID
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/tasks/%s/addProject", taskGID)
+	_, err = c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	return err
+}
+
+// GetTaskMemberships returns the projects a task is in and its section in
+// each of them
+func (c *Client) GetTaskMemberships(taskGID string) ([]Membership, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "memberships.project.gid,memberships.project.name,memberships.section.gid,memberships.section.name")
+
+	endpoint := fmt.Sprintf("/tasks/%s?%s", taskGID, params.Encode())
+	body, err := c.doRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return resp.Data.Memberships, nil
+}
+
+// SetParent makes a task a subtask of parentGID. If insertAfter is set, the
+// task is placed after that subtask; otherwise it is added at the end.
+func (c *Client) SetParent(taskGID, parentGID, insertAfter string) (*Task, error) {
+	data := map[string]interface{}{
+		"parent": parentGID,
+	}
+	if insertAfter != "" {
+		data["insert_after"] = insertAfter
+	}
+	return c.setParent(taskGID, data)
+}
+
+// RemoveParent turns a subtask into a top-level task
+func (c *Client) RemoveParent(taskGID string) (*Task, error) {
+	return c.setParent(taskGID, map[string]interface{}{"parent": nil})
+}
+
+func (c *Client) setParent(taskGID string, data map[string]interface{}) (*Task, error) {
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/tasks/%s/setParent", taskGID)
+	body, err := c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// DeleteTask deletes a task
+func (c *Client) DeleteTask(taskGID string) error {
+	endpoint := fmt.Sprintf("/tasks/%s", taskGID)
+	_, err := c.doRequest("DELETE", endpoint, nil)
+	return err
+}
+
+// Job represents an asynchronous Asana job, such as a task duplication
+type Job struct {
+	GID             string  `json:"gid"`
+	Status          string  `json:"status"` // not_started, in_progress, succeeded or failed
+	ResourceSubtype string  `json:"resource_subtype,omitempty"`
+	NewTask         *Entity `json:"new_task,omitempty"`
+	NewProject      *Entity `json:"new_project,omitempty"`
+}
+
+type JobResponse struct {
+	Data Job `json:"data"`
+}
+
+// DuplicateOptions contains options for duplicating a task
+type DuplicateOptions struct {
+	Name    string   // Name of the new task (required)
+	Include []string // Parts to copy: notes, assignee, subtasks, attachments, dependencies, ...
+}
+
+// DuplicateTask starts duplicating a task and returns the job doing the work
+func (c *Client) DuplicateTask(taskGID string, opts DuplicateOptions) (*Job, error) {
+	data := map[string]interface{}{
+		"name": opts.Name,
+	}
+	if len(opts.Include) > 0 {
+		data["include"] = strings.Join(opts.Include, ",")
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/tasks/%s/duplicate", taskGID)
+	body, err := c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp JobResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// DuplicateProject starts duplicating a project and returns the job doing
+// the work. include lists the parts to copy (members, notes, task_notes,
+// task_assignee, task_subtasks, ...). teamGID is the team for the new
+// project; empty keeps the original project's team.
+func (c *Client) DuplicateProject(gid string, name string, include []string, teamGID string) (*Job, error) {
+	data := map[string]interface{}{
+		"name": name,
+	}
+	if len(include) > 0 {
+		data["include"] = strings.Join(include, ",")
+	}
+	if teamGID != "" {
+		data["team"] = teamGID
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/projects/%s/duplicate", gid)
+	body, err := c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp JobResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// ProjectTemplate is a project template saved in Asana
+type ProjectTemplate struct {
+	GID         string  `json:"gid"`
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Team        *Entity `json:"team,omitempty"`
+}
+
+// ListProjectTemplates returns the project templates of a team, or with an
+// empty teamGID those of the whole workspace
+func (c *Client) ListProjectTemplates(teamGID string) ([]ProjectTemplate, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,description,team,team.name")
+
+	endpoint := "/project_templates"
+	if teamGID != "" {
+		endpoint = fmt.Sprintf("/teams/%s/project_templates", teamGID)
+	} else {
+		params.Set("workspace", c.workspace)
+	}
+	return collectPages[ProjectTemplate](c, endpoint, params, 0)
+}
+
+// InstantiateProjectTemplate starts creating a project from a template and
+// returns the job doing the work. teamGID is the team for the new project
+// (needed in organizations); publicTo sets its privacy: public_to_workspace,
+// private_to_team or private, empty for the template's default.
+func (c *Client) InstantiateProjectTemplate(templateGID string, name string, teamGID string, publicTo string) (*Job, error) {
+	data := map[string]interface{}{
+		"name": name,
+	}
+	if teamGID != "" {
+		data["team"] = teamGID
+	}
+	if publicTo != "" {
+		data["privacy_setting"] = publicTo
+	}
+
+	payload := map[string]interface{}{"data": data}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("/project_templates/%s/instantiateProject", templateGID)
+	body, err := c.doRequest("POST", endpoint, strings.NewReader(string(jsonBody)))
+	if err != nil {
+		return nil, err
+	}
+
+	var resp JobResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// GetJob returns the current state of a job. Jobs are never served from cache.
+func (c *Client) GetJob(jobGID string) (*Job, error) {
+	endpoint := fmt.Sprintf("/jobs/%s", jobGID)
+	body, err := c.doUncachedRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp JobResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// WaitForJob polls a job until it succeeds, fails, or timeout passes
+func (c *Client) WaitForJob(jobGID string, timeout time.Duration) (*Job, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		job, err := c.GetJob(jobGID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch job.Status {
+		case "succeeded":
+			return job, nil
+		case "failed":
+			return job, fmt.Errorf("job %s failed", jobGID)
+		}
+
+		if time.Now().After(deadline) {
+			return job, fmt.Errorf("job %s did not finish within %s (status: %s)", jobGID, timeout, job.Status)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// Workspace represents an Asana workspace or organization
+type Workspace struct {
+	GID            string `json:"gid"`
+	Name           string `json:"name"`
+	IsOrganization bool   `json:"is_organization"`
+
+	// EmailDomains are the organization's email domains; only set by
+	// GetWorkspace
+	EmailDomains []string `json:"email_domains,omitempty"`
+}
+
+type WorkspacesResponse struct {
+	Data []Workspace `json:"data"`
+}
+
+type WorkspaceResponse struct {
+	Data Workspace `json:"data"`
+}
+
+// GetWorkspace returns a workspace by GID
+func (c *Client) GetWorkspace(gid string) (*Workspace, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,is_organization,email_domains")
+
+	endpoint := fmt.Sprintf("/workspaces/%s?%s", gid, params.Encode())
+	body, err := c.doUncachedRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp WorkspaceResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, nil
+}
+
+// ListWorkspaces returns the workspaces the user has access to
+func (c *Client) ListWorkspaces() ([]Workspace, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,is_organization")
+
+	endpoint := "/workspaces?" + params.Encode()
+	body, err := c.doRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp WorkspacesResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return resp.Data, nil
+}
+
+// Team represents an Asana team
+type Team struct {
+	GID         string `json:"gid"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Permalink   string `json:"permalink_url,omitempty"`
+}
+
+// ListTeams returns the teams in the workspace (an organization), or with
+// mine set only the teams the current user is a member of
+func (c *Client) ListTeams(mine bool) ([]Team, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,description,permalink_url")
+
+	endpoint := fmt.Sprintf("/workspaces/%s/teams", c.workspace)
+	if mine {
+		endpoint = "/users/me/teams"
+		params.Set("organization", c.workspace)
+	}
+	return collectPages[Team](c, endpoint, params, 0)
+}
+
+// UsersResponse represents the API response for users
+type UsersResponse struct {
+	Data []User `json:"data"`
+}
+
+// UserResponse represents the API response for a single user
+type UserResponse struct {
+	Data User `json:"data"`
 }
 
-// GetTaskStories returns all stories (comments and activity) for a task
-func (c *Client) GetTaskStories(taskGID string) ([]Story, error) {
+// ListUsers returns all users in the workspace. optFields selects the
+// fields to fetch; empty uses the default set.
+func (c *Client) ListUsers(optFields string) ([]User, error) {
+	if optFields == "" {
+		optFields = "gid,name,email"
+	}
 	params := url.Values{}
-	params.Set("opt_fields", "gid,created_at,created_by,created_by.name,text,html_text,type,resource_subtype")
+	params.Set("opt_fields", optFields)
 
-	endpoint := fmt.Sprintf("/tasks/%s/stories?%s", taskGID, params.Encode())
+	endpoint := fmt.Sprintf("/workspaces/%s/users?%s", c.workspace, params.Encode())
 	body, err := c.doRequest("GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var resp StoriesResponse
+	var resp UsersResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
@@ -360,26 +2116,35 @@ func (c *Client) GetTaskStories(taskGID string) ([]Story, error) {
 	return resp.Data, nil
 }
 
-// ListProjects returns projects in the workspace
-func (c *Client) ListProjects(archived bool, limit int) ([]Project, error) {
-	params := url.Values{}
-	params.Set("archived", fmt.Sprintf("%t", archived))
+// TypeaheadResult is one match returned by the typeahead endpoint
+type TypeaheadResult struct {
+	GID          string `json:"gid"`
+	Name         string `json:"name"`
+	ResourceType string `json:"resource_type"`
+}
 
-	if limit > 0 {
-		params.Set("limit", fmt.Sprintf("%d", limit))
-	} else {
-		params.Set("limit", "100")
-	}
+type TypeaheadResponse struct {
+	Data []TypeaheadResult `json:"data"`
+}
 
-	params.Set("opt_fields", "gid,name,archived,color,created_at,permalink_url")
+// Typeahead finds objects of resourceType (task, project, user, tag, ...)
+// whose name matches query, returning at most count results
+func (c *Client) Typeahead(query, resourceType string, count int) ([]TypeaheadResult, error) {
+	params := url.Values{}
+	params.Set("resource_type", resourceType)
+	params.Set("query", query)
+	params.Set("opt_fields", "gid,name,resource_type")
+	if count > 0 {
+		params.Set("count", fmt.Sprintf("%d", count))
+	}
 
-	endpoint := fmt.Sprintf("/workspaces/%s/projects?%s", c.workspace, params.Encode())
+	endpoint := fmt.Sprintf("/workspaces/%s/typeahead?%s", c.workspace, params.Encode())
 	body, err := c.doRequest("GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var resp ProjectsResponse
+	var resp TypeaheadResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
@@ -387,62 +2152,48 @@ func (c *Client) ListProjects(archived bool, limit int) ([]Project, error) {
 	return resp.Data, nil
 }
 
-// CreateTaskOptions contains options for creating a new task
-type CreateTaskOptions struct {
-	Name      string
-	Notes     string
-	HTMLNotes string // Rich text description (HTML)
-	Assignee  string
-	DueOn     string
-	Projects  []string
-	Tags      []string
-	Parent    string // For subtasks
+// Tag represents an Asana tag
+type Tag struct {
+	GID   string `json:"gid"`
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
 }
 
-// CreateTask creates a new task in the workspace
-func (c *Client) CreateTask(opts CreateTaskOptions) (*Task, error) {
-	data := map[string]interface{}{
-		"name": opts.Name,
-	}
+// ListTags returns all tags in the workspace
+func (c *Client) ListTags() ([]Tag, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,color")
+	params.Set("limit", "100")
 
-	if opts.HTMLNotes != "" {
-		data["html_notes"] = opts.HTMLNotes
-	} else if opts.Notes != "" {
-		data["notes"] = opts.Notes
-	}
-	if opts.Assignee != "" {
-		data["assignee"] = opts.Assignee
-	}
-	if opts.DueOn != "" {
-		data["due_on"] = opts.DueOn
-	}
-	if len(opts.Projects) > 0 {
-		data["projects"] = opts.Projects
-	}
-	if len(opts.Tags) > 0 {
-		data["tags"] = opts.Tags
-	}
-	if opts.Parent != "" {
-		data["parent"] = opts.Parent
+	var tags []Tag
+	endpoint := fmt.Sprintf("/workspaces/%s/tags", c.workspace)
+	err := c.eachPage(endpoint, params, func(data json.RawMessage) error {
+		var page []Tag
+		if err := json.Unmarshal(data, &page); err != nil {
+			return fmt.Errorf("parsing response: %w", err)
+		}
+		tags = append(tags, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// If no project specified and not a subtask, we need workspace
-	if len(opts.Projects) == 0 && opts.Parent == "" {
-		data["workspace"] = c.workspace
-	}
+	return tags, nil
+}
 
-	payload := map[string]interface{}{"data": data}
-	jsonBody, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("marshaling request: %w", err)
-	}
+// GetMe returns the current authenticated user
+func (c *Client) GetMe() (*User, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,email")
 
-	body, err := c.doRequest("POST", "/tasks", strings.NewReader(string(jsonBody)))
+	endpoint := "/users/me?" + params.Encode()
+	body, err := c.doRequest("GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var resp TaskResponse
+	var resp UserResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
@@ -450,51 +2201,18 @@ func (c *Client) CreateTask(opts CreateTaskOptions) (*Task, error) {
 	return &resp.Data, nil
 }
 
-// UpdateTaskOptions contains options for updating a task
-type UpdateTaskOptions struct {
-	Name      *string
-	Notes     *string
-	HTMLNotes *string // Rich text description (HTML)
-	Assignee  *string
-	DueOn     *string
-	Completed *bool
-}
-
-// UpdateTask updates an existing task
-func (c *Client) UpdateTask(taskGID string, opts UpdateTaskOptions) (*Task, error) {
-	data := map[string]interface{}{}
-
-	if opts.Name != nil {
-		data["name"] = *opts.Name
-	}
-	if opts.HTMLNotes != nil {
-		data["html_notes"] = *opts.HTMLNotes
-	} else if opts.Notes != nil {
-		data["notes"] = *opts.Notes
-	}
-	if opts.Assignee != nil {
-		data["assignee"] = *opts.Assignee
-	}
-	if opts.DueOn != nil {
-		data["due_on"] = *opts.DueOn
-	}
-	if opts.Completed != nil {
-		data["completed"] = *opts.Completed
-	}
-
-	payload := map[string]interface{}{"data": data}
-	jsonBody, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("marshaling request: %w", err)
-	}
+// GetUser returns a user by GID, with their photo and workspaces
+func (c *Client) GetUser(gid string) (*User, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,email,photo,workspaces,workspaces.name")
 
-	endpoint := fmt.Sprintf("/tasks/%s", taskGID)
-	body, err := c.doRequest("PUT", endpoint, strings.NewReader(string(jsonBody)))
+	endpoint := fmt.Sprintf("/users/%s?%s", gid, params.Encode())
+	body, err := c.doRequest("GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var resp TaskResponse
+	var resp UserResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
@@ -502,71 +2220,101 @@ func (c *Client) UpdateTask(taskGID string, opts UpdateTaskOptions) (*Task, erro
 	return &resp.Data, nil
 }
 
-// CompleteTask marks a task as completed
-func (c *Client) CompleteTask(taskGID string) (*Task, error) {
-	completed := true
-	return c.UpdateTask(taskGID, UpdateTaskOptions{Completed: &completed})
+// MyGID returns the GID of the authenticated user. It is looked up once
+// and then remembered, so "me" can be turned into a GID wherever an
+// endpoint doesn't accept the literal.
+func (c *Client) MyGID() (string, error) {
+	c.me.mu.Lock()
+	defer c.me.mu.Unlock()
+
+	if c.me.gid == "" {
+		user, err := c.GetMe()
+		if err != nil {
+			return "", err
+		}
+		c.me.gid = user.GID
+	}
+	return c.me.gid, nil
 }
 
-// ReopenTask marks a task as not completed
-func (c *Client) ReopenTask(taskGID string) (*Task, error) {
-	completed := false
-	return c.UpdateTask(taskGID, UpdateTaskOptions{Completed: &completed})
-}
+// Ping fetches the current user, bypassing the cache, and returns how long
+// the round trip took
+func (c *Client) Ping() (*User, time.Duration, error) {
+	params := url.Values{}
+	params.Set("opt_fields", "gid,name,email")
 
-// DeleteTask deletes a task
-func (c *Client) DeleteTask(taskGID string) error {
-	endpoint := fmt.Sprintf("/tasks/%s", taskGID)
-	_, err := c.doRequest("DELETE", endpoint, nil)
-	return err
+	start := time.Now()
+	body, err := c.doUncachedRequest("GET", "/users/me?"+params.Encode(), nil)
+	elapsed := time.Since(start)
+	if err != nil {
+		return nil, elapsed, err
+	}
+
+	var resp UserResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, elapsed, fmt.Errorf("parsing response: %w", err)
+	}
+
+	return &resp.Data, elapsed, nil
 }
 
-// UsersResponse represents the API response for users
-type UsersResponse struct {
-	Data []User `json:"data"`
+// UserTaskList represents a user's "My Tasks" list
+type UserTaskList struct {
+	GID  string `json:"gid"`
+	Name string `json:"name,omitempty"`
 }
 
-// UserResponse represents the API response for a single user
-type UserResponse struct {
-	Data User `json:"data"`
+type UserTaskListResponse struct {
+	Data UserTaskList `json:"data"`
 }
 
-// ListUsers returns all users in the workspace
-func (c *Client) ListUsers() ([]User, error) {
+// GetUserTaskList returns the My Tasks list of a user in a workspace; an
+// empty workspaceGID means the configured workspace
+func (c *Client) GetUserTaskList(userGID, workspaceGID string) (*UserTaskList, error) {
+	if workspaceGID == "" {
+		workspaceGID = c.workspace
+	}
 	params := url.Values{}
-	params.Set("opt_fields", "gid,name,email")
+	params.Set("workspace", workspaceGID)
 
-	endpoint := fmt.Sprintf("/workspaces/%s/users?%s", c.workspace, params.Encode())
+	endpoint := fmt.Sprintf("/users/%s/user_task_list?%s", userGID, params.Encode())
 	body, err := c.doRequest("GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	var resp UsersResponse
+	var resp UserTaskListResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
 		return nil, fmt.Errorf("parsing response: %w", err)
 	}
 
-	return resp.Data, nil
+	return &resp.Data, nil
 }
 
-// GetMe returns the current authenticated user
-func (c *Client) GetMe() (*User, error) {
-	params := url.Values{}
-	params.Set("opt_fields", "gid,name,email")
+// GetMyTaskList returns the incomplete tasks in the current user's My Tasks,
+// in the order the user arranged them, with their My Tasks section.
+// A limit of 0 returns all of them.
+func (c *Client) GetMyTaskList(limit int) ([]Task, error) {
+	return c.ListTasksByUser("me", limit)
+}
 
-	endpoint := "/users/me?" + params.Encode()
-	body, err := c.doRequest("GET", endpoint, nil)
+// ListTasksByUser returns the incomplete tasks in a user's My Tasks in the
+// configured workspace, in that user's order and with their My Tasks
+// section. Unlike filtering by assignee, this reflects how the user arranged
+// them. The API refuses (403) lists the current user can't see.
+// A limit of 0 returns all of them.
+func (c *Client) ListTasksByUser(userGID string, limit int) ([]Task, error) {
+	list, err := c.GetUserTaskList(userGID, "")
 	if err != nil {
 		return nil, err
 	}
 
-	var resp UserResponse
-	if err := json.Unmarshal(body, &resp); err != nil {
-		return nil, fmt.Errorf("parsing response: %w", err)
-	}
+	params := url.Values{}
+	params.Set("completed_since", "now")
+	params.Set("opt_fields", c.fields("gid,name,completed,due_on,assignee_section,assignee_section.name,projects,projects.name,permalink_url"))
 
-	return &resp.Data, nil
+	endpoint := fmt.Sprintf("/user_task_lists/%s/tasks", list.GID)
+	return collectPages[Task](c, endpoint, params, limit)
 }
 
 // TaskSummary represents task counts for summary reporting
@@ -656,7 +2404,7 @@ type AttachmentsResponse struct {
 // ListAttachments returns attachments on a task
 func (c *Client) ListAttachments(taskGID string) ([]Attachment, error) {
 	params := url.Values{}
-	params.Set("opt_fields", "gid,name,resource_subtype,created_at,host,size")
+	params.Set("opt_fields", "gid,name,resource_subtype,created_at,host,size,permanent_url")
 
 	endpoint := fmt.Sprintf("/tasks/%s/attachments?%s", taskGID, params.Encode())
 	body, err := c.doRequest("GET", endpoint, nil)
@@ -715,8 +2463,8 @@ func (c *Client) doMultipartRequest(endpoint, filePath string) ([]byte, error) {
 		return nil, fmt.Errorf("closing multipart writer: %w", err)
 	}
 
-	reqURL := baseURL + endpoint
-	req, err := http.NewRequest("POST", reqURL, &buf)
+	reqURL := c.baseURL + endpoint
+	req, err := http.NewRequestWithContext(c.context(), "POST", reqURL, &buf)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -725,35 +2473,67 @@ func (c *Client) doMultipartRequest(endpoint, filePath string) ([]byte, error) {
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	resp, err := c.httpClient.Do(req)
+	return c.send(req, fmt.Sprintf("<multipart upload of %s, %d bytes>", filepath.Base(filePath), buf.Len()))
+}
+
+// UploadAttachment uploads a file to a task
+func (c *Client) UploadAttachment(taskGID, filePath string) (*Attachment, error) {
+	endpoint := fmt.Sprintf("/tasks/%s/attachments", taskGID)
+	body, err := c.doMultipartRequest(endpoint, filePath)
 	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+		return nil, err
+	}
+	if c.cache != nil {
+		c.cache.invalidate(endpoint)
 	}
-	defer resp.Body.Close()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+	var resp AttachmentResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parsing response: %w", err)
 	}
 
-	if resp.StatusCode >= 400 {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err == nil && len(errResp.Errors) > 0 {
-			return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Errors[0].Message)
+	return &resp.Data, nil
+}
+
+// AttachURL attaches a link to an external resource (a Google Doc, pull
+// request, design, ...) to a task. An empty name uses the URL.
+func (c *Client) AttachURL(taskGID, url, name string) (*Attachment, error) {
+	if name == "" {
+		name = url
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	fields := [][2]string{
+		{"parent", taskGID},
+		{"resource_subtype", "external"},
+		{"url", url},
+		{"name", name},
+	}
+	for _, f := range fields {
+		if err := writer.WriteField(f[0], f[1]); err != nil {
+			return nil, fmt.Errorf("creating form field: %w", err)
 		}
-		return nil, fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("closing multipart writer: %w", err)
 	}
 
-	return respBody, nil
-}
+	req, err := http.NewRequestWithContext(c.context(), "POST", c.baseURL+"/attachments", &buf)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-// UploadAttachment uploads a file to a task
-func (c *Client) UploadAttachment(taskGID, filePath string) (*Attachment, error) {
-	endpoint := fmt.Sprintf("/tasks/%s/attachments", taskGID)
-	body, err := c.doMultipartRequest(endpoint, filePath)
+	body, err := c.send(req, fmt.Sprintf("<link attachment %s to %s>", url, taskGID))
 	if err != nil {
 		return nil, err
 	}
+	if c.cache != nil {
+		c.cache.invalidate(fmt.Sprintf("/tasks/%s/attachments", taskGID))
+	}
 
 	var resp AttachmentResponse
 	if err := json.Unmarshal(body, &resp); err != nil {
@@ -763,33 +2543,167 @@ func (c *Client) UploadAttachment(taskGID, filePath string) (*Attachment, error)
 	return &resp.Data, nil
 }
 
-// DownloadAttachment downloads an attachment file to disk
-func (c *Client) DownloadAttachment(attachment *Attachment, destPath string) error {
+// DownloadOptions controls how an attachment is downloaded
+type DownloadOptions struct {
+	// Resume continues a partial download left behind as destPath.part
+	Resume bool
+	// Progress, if set, is called as data arrives with the number of bytes
+	// written so far and the expected total (-1 if unknown)
+	Progress func(written, total int64)
+}
+
+// downloadBackoff is the wait before the first retry of a failed download;
+// it grows with each attempt
+const downloadBackoff = time.Second
+
+// DownloadAttachment downloads an attachment to the specified path. The
+// data is written to destPath.part, which is renamed to destPath once the
+// download is complete and its size matches the attachment's, so destPath
+// never holds a partial file. Network errors and 5xx responses are retried,
+// continuing where the previous attempt stopped; if all attempts fail the
+// .part file is kept for opts.Resume. An existing file is overwritten. The
+// download URL is absolute, so the client's base URL doesn't apply, but
+// its HTTP client (and any timeout set on it) and context do.
+func (c *Client) DownloadAttachment(attachment *Attachment, destPath string, opts DownloadOptions) error {
 	if attachment.DownloadURL == "" {
 		return fmt.Errorf("attachment has no download URL")
 	}
 
-	resp, err := c.httpClient.Get(attachment.DownloadURL)
+	partPath := destPath + ".part"
+	if opts.Resume {
+		if info, err := os.Stat(destPath); err == nil && attachment.Size > 0 && info.Size() == attachment.Size {
+			return nil // already complete
+		}
+	} else {
+		os.Remove(partPath)
+	}
+
+	for attempt := 0; ; attempt++ {
+		retry, err := c.downloadOnce(attachment, partPath, opts.Progress)
+		if err == nil {
+			break
+		}
+		if !retry || attempt == maxRetries {
+			return err
+		}
+
+		wait := time.Duration(attempt+1) * downloadBackoff
+		c.logf("< %v, retrying in %s\n", err, wait)
+		select {
+		case <-time.After(wait):
+		case <-c.context().Done():
+			return c.context().Err()
+		}
+	}
+
+	info, err := os.Stat(partPath)
 	if err != nil {
-		return fmt.Errorf("downloading file: %w", err)
+		return fmt.Errorf("checking download: %w", err)
+	}
+	if attachment.Size > 0 && info.Size() != attachment.Size {
+		os.Remove(partPath)
+		return fmt.Errorf("downloaded %d bytes but the attachment is %d bytes", info.Size(), attachment.Size)
+	}
+	if err := os.Rename(partPath, destPath); err != nil {
+		return fmt.Errorf("saving file: %w", err)
+	}
+	return nil
+}
+
+// downloadOnce makes one attempt at downloading an attachment into
+// partPath, continuing from whatever it already holds. retry reports
+// whether the error is worth another attempt.
+func (c *Client) downloadOnce(attachment *Attachment, partPath string, progress func(written, total int64)) (retry bool, err error) {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+	if attachment.Size > 0 && offset == attachment.Size {
+		return false, nil
+	}
+	if attachment.Size > 0 && offset > attachment.Size {
+		os.Remove(partPath)
+		return false, fmt.Errorf("%s is larger than the attachment (%d > %d bytes)", partPath, offset, attachment.Size)
+	}
+
+	req, err := http.NewRequestWithContext(c.context(), "GET", attachment.DownloadURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("creating request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	// Downloads count against the request limit like any API call
+	release, err := c.limiter.acquire(req.Context())
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return req.Context().Err() == nil, fmt.Errorf("downloading file: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("download failed with status %d", resp.StatusCode)
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retry, fmt.Errorf("download failed with status %d", resp.StatusCode)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if offset > 0 && resp.StatusCode == http.StatusPartialContent {
+		flags = os.O_WRONLY | os.O_APPEND
+	} else {
+		offset = 0 // the server sent the whole file
 	}
 
-	out, err := os.Create(destPath)
+	out, err := os.OpenFile(partPath, flags, 0644)
 	if err != nil {
-		return fmt.Errorf("creating file: %w", err)
+		return false, fmt.Errorf("creating file: %w", err)
 	}
 	defer out.Close()
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+	total := attachment.Size
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	} else if total == 0 {
+		total = -1
 	}
 
-	return nil
+	var w io.Writer = out
+	if progress != nil {
+		w = &progressWriter{w: out, written: offset, total: total, fn: progress}
+		progress(offset, total)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		// Failing to write the file won't get better by trying again;
+		// a connection that broke off might
+		var pathErr *fs.PathError
+		return !errors.As(err, &pathErr) && req.Context().Err() == nil, fmt.Errorf("writing file: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return false, fmt.Errorf("writing file: %w", err)
+	}
+
+	return false, nil
+}
+
+// progressWriter reports the number of bytes written through it
+type progressWriter struct {
+	w       io.Writer
+	written int64
+	total   int64
+	fn      func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.written += int64(n)
+	p.fn(p.written, p.total)
+	return n, err
 }
 
 // DeleteAttachment deletes an attachment
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mauricejumelet/asana-cli/internal/config"
+)
+
+func TestUpdateTaskDueDates(t *testing.T) {
+	empty, date, dueAt := "", "2024-05-01", "2024-05-01T15:00:00Z"
+	tests := []struct {
+		name string
+		opts UpdateTaskOptions
+		want map[string]interface{}
+	}{
+		{
+			name: "clear due",
+			opts: UpdateTaskOptions{DueOn: &empty},
+			want: map[string]interface{}{"due_on": nil, "due_at": nil},
+		},
+		{
+			name: "due date",
+			opts: UpdateTaskOptions{DueOn: &date},
+			want: map[string]interface{}{"due_on": date},
+		},
+		{
+			name: "due time",
+			opts: UpdateTaskOptions{DueOn: &date, DueAt: &dueAt},
+			want: map[string]interface{}{"due_at": dueAt},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != "/tasks/1" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				body, _ = io.ReadAll(r.Body)
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, `{"data":{"gid":"1","name":"Task"}}`)
+			}))
+			defer srv.Close()
+
+			client := NewClient(&config.Config{Token: "token", BaseURL: srv.URL, Workspace: "1"})
+			if _, err := client.UpdateTask("1", tt.opts); err != nil {
+				t.Fatal(err)
+			}
+
+			var got struct {
+				Data map[string]interface{} `json:"data"`
+			}
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("invalid request body %s: %v", body, err)
+			}
+			if !reflect.DeepEqual(got.Data, tt.want) {
+				t.Errorf("request data = %s, want %v", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadAttachmentUsesLimiter(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		io.WriteString(w, "hello")
+	}))
+	defer srv.Close()
+
+	client := NewClient(&config.Config{Token: "token", BaseURL: srv.URL, Workspace: "1"})
+	client.SetMaxConcurrentRequests(1)
+
+	// Hold the only slot, as another request in flight would
+	release, err := client.limiter.acquire(client.context())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "file.txt")
+	done := make(chan error, 1)
+	go func() {
+		done <- client.DownloadAttachment(&Attachment{GID: "1", DownloadURL: srv.URL + "/file", Size: 5}, dest, DownloadOptions{})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Fatalf("download made %d requests while the limit was reached", n)
+	}
+
+	release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("download didn't finish after the slot was released")
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("download made %d requests, want 1", n)
+	}
+	if data, err := os.ReadFile(dest); err != nil || string(data) != "hello" {
+		t.Errorf("downloaded %q, %v", data, err)
+	}
+}
+
+func TestRetryOnConflictRefetches(t *testing.T) {
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPut && len(requests) == 1 {
+			w.WriteHeader(http.StatusConflict)
+			io.WriteString(w, `{"errors":[{"message":"conflict"}]}`)
+			return
+		}
+		io.WriteString(w, `{"data":{"gid":"1","name":"Task"}}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient(&config.Config{Token: "token", BaseURL: srv.URL, Workspace: "1"})
+	client.SetRetryOnConflict(true)
+	name := "Renamed"
+	if _, err := client.UpdateTask("1", UpdateTaskOptions{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"PUT /tasks/1", "GET /tasks/1", "PUT /tasks/1"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("requests = %q, want %q", requests, want)
+	}
+}
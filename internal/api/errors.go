@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Errors for common API failures. An APIError with a matching status code
+// satisfies errors.Is for these.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrConflict     = errors.New("modified concurrently")
+)
+
+// errNotModified is returned for a 304 answer to a conditional request;
+// doRequest then serves the cached body
+var errNotModified = errors.New("not modified")
+
+// APIError is returned for any response with a status code of 400 or above
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if errors.Is(e, ErrConflict) {
+		return fmt.Sprintf("API error (%d): the item was modified concurrently by someone else: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+}
+
+// Unwrap returns the sentinel error for the status code, if there is one
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return ErrConflict
+	default:
+		return nil
+	}
+}
+
+// IsStatus reports whether err is an APIError with the given status code
+func IsStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
+
+// ExplainAuthError replaces 401 and 403 API errors with messages that tell
+// the user what to do about them. Other errors are returned unchanged.
+func ExplainAuthError(err error) error {
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return fmt.Errorf("your ASANA_TOKEN is invalid or expired — get a new one at https://app.asana.com/0/my-apps")
+	case errors.Is(err, ErrForbidden):
+		return fmt.Errorf("your ASANA_TOKEN is valid but lacks permission for this request (check the token's access and scopes): %w", err)
+	default:
+		return err
+	}
+}
+
+// Verify checks that the configured token is accepted by the API
+func (c *Client) Verify() (*User, error) {
+	user, err := c.GetMe()
+	if err != nil {
+		return nil, ExplainAuthError(err)
+	}
+	return user, nil
+}
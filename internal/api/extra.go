@@ -0,0 +1,78 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// knownFields caches the JSON names of each struct type's fields
+var knownFields sync.Map // reflect.Type -> map[string]bool
+
+// jsonNames returns the JSON field names a struct type decodes
+func jsonNames(t reflect.Type) map[string]bool {
+	if names, ok := knownFields.Load(t); ok {
+		return names.(map[string]bool)
+	}
+
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	knownFields.Store(t, names)
+	return names
+}
+
+// unmarshalWithExtra decodes data into v, a pointer to a struct, and keeps
+// every field that v has no place for in extra. resource_type is dropped, as
+// the API sends it on every record and the type is already known.
+func unmarshalWithExtra(data []byte, v interface{}, extra *map[string]json.RawMessage) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	known := jsonNames(reflect.TypeOf(v).Elem())
+	for name := range all {
+		if known[strings.ToLower(name)] || name == "resource_type" {
+			delete(all, name)
+		}
+	}
+
+	*extra = nil
+	if len(all) > 0 {
+		*extra = all
+	}
+	return nil
+}
+
+// marshalWithExtra encodes v, a struct, followed by the fields in extra
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	more, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(data, []byte("{}")) {
+		return more, nil
+	}
+	// Splice the two objects: {"a":1} + {"b":2} -> {"a":1,"b":2}
+	return append(append(data[:len(data)-1], ','), more[1:]...), nil
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxConcurrentRequests is how many API requests a client has in
+// flight at once unless SetMaxConcurrentRequests says otherwise. Asana
+// allows more, but this leaves room for other tools using the same token.
+const DefaultMaxConcurrentRequests = 5
+
+// requestLimiter bounds the requests in flight across every goroutine
+// using a client and its copies, and holds all of them back after a 429
+// so they don't keep running into the rate limit one by one
+type requestLimiter struct {
+	slots chan struct{}
+
+	mu    sync.Mutex
+	until time.Time // no new requests before this time
+}
+
+func newRequestLimiter(n int) *requestLimiter {
+	l := &requestLimiter{}
+	l.setMax(n)
+	return l
+}
+
+func (l *requestLimiter) setMax(n int) {
+	l.slots = nil
+	if n > 0 {
+		l.slots = make(chan struct{}, n)
+	}
+}
+
+// acquire waits for a free slot and for any rate-limit pause to pass. The
+// returned function releases the slot.
+func (l *requestLimiter) acquire(ctx context.Context) (func(), error) {
+	release := func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+			release = func() { <-l.slots }
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	l.mu.Lock()
+	wait := time.Until(l.until)
+	l.mu.Unlock()
+	if wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// pause holds back new requests for d
+func (l *requestLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := time.Now().Add(d); until.After(l.until) {
+		l.until = until
+	}
+}
+
+// SetMaxConcurrentRequests limits how many requests the client and its
+// copies send at the same time; 0 or less removes the limit. It must be
+// called before the client is used.
+func (c *Client) SetMaxConcurrentRequests(n int) {
+	c.limiter.setMax(n)
+}
@@ -1,17 +1,30 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
-
-	"github.com/joho/godotenv"
+	"time"
 )
 
 type Config struct {
-	Token     string
-	Workspace string
+	Token        string
+	TokenCommand string // Command the token was read from, if any
+	Workspace    string
+	BaseURL      string        // API base URL; empty means the public Asana API
+	TZ           string        // Time zone for displayed times; empty means local time
+	AuditLog     string        // File that mutating requests are logged to; empty disables it
+	CacheTTL     time.Duration // How long GET responses are cached; 0 disables the cache
+	Profile      string        // Profile selected in TOML or YAML config files, if any
+	Files        []string      // Config files that were loaded, highest priority first
+
+	// Used by tasks create and tasks list when -p or -a isn't given
+	DefaultProject  string // Project GID or name
+	DefaultAssignee string // Assignee GID, email, name or "me"
 }
 
 // ConfigLocations returns the list of config file locations that are checked
@@ -24,65 +37,212 @@ func ConfigLocations() []string {
 	homeDir, err := os.UserHomeDir()
 	if err == nil {
 		// XDG-style config directory
-		locations = append(locations, filepath.Join(homeDir, ".config", "asana-cli", ".env"))
+		dir := filepath.Join(homeDir, ".config", "asana-cli")
+		locations = append(locations,
+			filepath.Join(dir, ".env"),
+			filepath.Join(dir, "config.toml"),
+			filepath.Join(dir, "config.yaml"),
+			filepath.Join(dir, "config.yml"),
+		)
 	}
 
 	return locations
 }
 
-// Load loads configuration from environment variables and optional .env files.
-// The configFile parameter allows specifying a custom config file path.
+// DefaultConfigFile returns the per-user config file written by config init
+func DefaultConfigFile() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "asana-cli", ".env"), nil
+}
+
+// TemplatesDir returns the directory where task templates are stored
+func TemplatesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "asana-cli", "templates"), nil
+}
+
+// Load loads configuration from environment variables and optional config
+// files. The configFile parameter allows specifying a custom config file path.
 // If empty, the default locations are checked in order:
 //  1. .env in current directory
 //  2. ~/.config/asana-cli/.env
+//  3. ~/.config/asana-cli/config.toml, config.yaml or config.yml
+//
+// Files ending in .toml, .yaml or .yml are read as TOML or YAML, anything
+// else as .env. In TOML and YAML files, profile (or, when that is empty,
+// ASANA_PROFILE) selects a table under profiles whose settings override the
+// top-level ones; it is an error if no loaded file has that profile.
 //
 // Environment variables always take precedence over file values.
-func Load(configFile string) (*Config, error) {
+// The token is either ASANA_TOKEN or the output of tokenCommand (or, when
+// that is empty, ASANA_TOKEN_COMMAND); setting both is an error.
+// When requireWorkspace is false, a missing ASANA_WORKSPACE is not an error
+// (e.g. when the workspace is given on the command line).
+func Load(configFile, profile, tokenCommand string, requireWorkspace bool) (*Config, error) {
+	var files []string
+	if profile == "" {
+		profile = os.Getenv("ASANA_PROFILE")
+	}
+	profileFound := false
+
 	// If a specific config file is provided, load only that one
 	if configFile != "" {
-		if err := godotenv.Load(configFile); err != nil {
+		found, err := loadFile(configFile, profile)
+		if err != nil {
 			return nil, fmt.Errorf("failed to load config file %s: %w", configFile, err)
 		}
+		files = append(files, configFile)
+		profileFound = found
 	} else {
-		// Try all default locations (godotenv.Load won't overwrite existing vars,
+		// Try all default locations (loadFile won't overwrite existing vars,
 		// so earlier files take precedence)
 		for _, loc := range ConfigLocations() {
-			if _, err := os.Stat(loc); err == nil {
-				_ = godotenv.Load(loc)
+			if _, err := os.Stat(loc); err != nil {
+				continue
 			}
+			found, err := loadFile(loc, profile)
+			if err != nil {
+				// A broken .env is skipped as before, but a structured file
+				// is only there on purpose, so its mistakes are reported
+				if isStructured(loc) {
+					return nil, fmt.Errorf("failed to load config file %s: %w", loc, err)
+				}
+				continue
+			}
+			files = append(files, loc)
+			profileFound = profileFound || found
 		}
 	}
 
+	if profile != "" && !profileFound {
+		return nil, fmt.Errorf("profile %q not found (profiles are defined under profiles in a .toml or .yaml config file)", profile)
+	}
+
 	token := os.Getenv("ASANA_TOKEN")
+	if tokenCommand == "" {
+		tokenCommand = os.Getenv("ASANA_TOKEN_COMMAND")
+	}
+	if token != "" && tokenCommand != "" {
+		return nil, fmt.Errorf("both ASANA_TOKEN and a token command are set; use only one")
+	}
+	if tokenCommand != "" {
+		var err error
+		if token, err = runTokenCommand(tokenCommand); err != nil {
+			return nil, err
+		}
+	}
 	if token == "" {
 		return nil, fmt.Errorf("ASANA_TOKEN not set.\n\n%s", configHelp())
 	}
 
 	workspace := os.Getenv("ASANA_WORKSPACE")
-	if workspace == "" {
+	if workspace == "" && requireWorkspace {
 		return nil, fmt.Errorf("ASANA_WORKSPACE not set.\n\n%s", configHelp())
 	}
 
+	var cacheTTL time.Duration
+	if s := os.Getenv("ASANA_CACHE_TTL"); s != "" {
+		var err error
+		if cacheTTL, err = time.ParseDuration(s); err != nil {
+			return nil, fmt.Errorf("invalid ASANA_CACHE_TTL %q (use a duration like 60s or 5m)", s)
+		}
+	}
+
 	return &Config{
-		Token:     token,
-		Workspace: workspace,
+		Token:        token,
+		TokenCommand: tokenCommand,
+		Workspace:    workspace,
+		Profile:      profile,
+		BaseURL:      os.Getenv("ASANA_BASE_URL"),
+		TZ:           os.Getenv("ASANA_TZ"),
+		AuditLog:     os.Getenv("ASANA_AUDIT_LOG"),
+		CacheTTL:     cacheTTL,
+		Files:        files,
+
+		DefaultProject:  os.Getenv("ASANA_DEFAULT_PROJECT"),
+		DefaultAssignee: os.Getenv("ASANA_DEFAULT_ASSIGNEE"),
 	}, nil
 }
 
+// loadFile sets the environment variables from a config file that aren't
+// set already, and reports whether the file has the given profile
+func loadFile(path, profile string) (bool, error) {
+	values, found, err := readFile(path, profile)
+	if err != nil {
+		return false, err
+	}
+	for k, v := range values {
+		if _, ok := os.LookupEnv(k); !ok {
+			os.Setenv(k, v)
+		}
+	}
+	return found, nil
+}
+
+// runTokenCommand runs command through the shell and returns its trimmed
+// output. The command's stdin and stderr are passed through so that secret
+// stores can prompt to unlock.
+func runTokenCommand(command string) (string, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	var out bytes.Buffer
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+
+	// The output is never included in errors, as it may be a partial token
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("token command %q failed: %w", command, err)
+	}
+	token := strings.TrimSpace(out.String())
+	if token == "" {
+		return "", fmt.Errorf("token command %q printed nothing", command)
+	}
+	return token, nil
+}
+
 func configHelp() string {
 	locations := ConfigLocations()
 	var sb strings.Builder
 
+	sb.WriteString("Run 'asana config init' to create a config file interactively.\n\n")
 	sb.WriteString("Configuration can be provided via:\n")
-	sb.WriteString("  1. Environment variables (ASANA_TOKEN, ASANA_WORKSPACE)\n")
-	sb.WriteString("  2. A .env file in one of these locations:\n")
+	sb.WriteString("  1. Environment variables (ASANA_TOKEN or ASANA_TOKEN_COMMAND, ASANA_WORKSPACE)\n")
+	sb.WriteString("  2. A config file in one of these locations:\n")
 	for _, loc := range locations {
 		sb.WriteString(fmt.Sprintf("     - %s\n", loc))
 	}
 	sb.WriteString("  3. A custom config file via --config flag\n")
+	sb.WriteString("\nThe --workspace flag overrides ASANA_WORKSPACE for a single command.\n")
 	sb.WriteString("\nExample .env file:\n")
 	sb.WriteString("  ASANA_TOKEN=your_personal_access_token\n")
 	sb.WriteString("  ASANA_WORKSPACE=your_workspace_gid\n")
+	sb.WriteString("\nConfig files ending in .toml, .yaml or .yml use lower-case keys instead, and\n")
+	sb.WriteString("can hold named profiles, selected with --profile or ASANA_PROFILE:\n")
+	sb.WriteString("  token = \"your_personal_access_token\"\n")
+	sb.WriteString("  workspace = \"your_workspace_gid\"\n")
+	sb.WriteString("\n  [profiles.client]\n")
+	sb.WriteString("  token_command = \"pass asana/client\"\n")
+	sb.WriteString("  workspace = \"another_workspace_gid\"\n")
+	sb.WriteString("\nTo keep the token in a password manager or keychain, set ASANA_TOKEN_COMMAND\n")
+	sb.WriteString("(or --token-command) to a command that prints it, e.g. \"pass asana/token\".\n")
+	sb.WriteString("\nSet ASANA_BASE_URL (or --base-url) to use a proxy or mock server.\n")
+	sb.WriteString("Set ASANA_TZ (or --tz) to show times in a time zone other than your local one.\n")
+	sb.WriteString("Set ASANA_AUDIT_LOG to a file path to log every change made through the CLI.\n")
+	sb.WriteString("Pass --cache to cache API responses for 60s, or set ASANA_CACHE_TTL (or --cache-ttl)\n")
+	sb.WriteString("to a duration like 5m to cache them for that long.\n")
+	sb.WriteString("Set ASANA_DEFAULT_PROJECT and ASANA_DEFAULT_ASSIGNEE to the project and assignee\n")
+	sb.WriteString("that tasks create and tasks list use when -p or -a isn't given (--no-defaults skips them).\n")
 	sb.WriteString("\nGet your token at: https://app.asana.com/0/my-apps")
 
 	return sb.String()
@@ -96,6 +256,6 @@ func PrintConfigHelp() {
 	fmt.Println(configHelp())
 	fmt.Println()
 	fmt.Println("Finding your Workspace GID:")
-	fmt.Println("  Run 'asana workspaces' after setting ASANA_TOKEN to list your workspaces,")
+	fmt.Println("  Run 'asana workspaces list' after setting ASANA_TOKEN to list your workspaces,")
 	fmt.Println("  or find it in your Asana URL: https://app.asana.com/0/<workspace_gid>/...")
 }
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// fileKeys maps the keys of TOML and YAML config files to the environment
+// variables they stand for
+var fileKeys = map[string]string{
+	"token":            "ASANA_TOKEN",
+	"token_command":    "ASANA_TOKEN_COMMAND",
+	"workspace":        "ASANA_WORKSPACE",
+	"base_url":         "ASANA_BASE_URL",
+	"tz":               "ASANA_TZ",
+	"audit_log":        "ASANA_AUDIT_LOG",
+	"cache_ttl":        "ASANA_CACHE_TTL",
+	"default_project":  "ASANA_DEFAULT_PROJECT",
+	"default_assignee": "ASANA_DEFAULT_ASSIGNEE",
+}
+
+// isStructured reports whether path is a TOML or YAML config file rather
+// than a .env one
+func isStructured(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// FileContents returns a config file setting token and workspace, in the
+// format path's extension calls for
+func FileContents(path, token, workspace string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return fmt.Sprintf("token = %s\nworkspace = %s\n", strconv.Quote(token), strconv.Quote(workspace))
+	case ".yaml", ".yml":
+		return fmt.Sprintf("token: %s\nworkspace: %s\n", strconv.Quote(token), strconv.Quote(workspace))
+	}
+	return fmt.Sprintf("ASANA_TOKEN=%s\nASANA_WORKSPACE=%s\n", token, workspace)
+}
+
+// Profile is a named profile in a TOML or YAML config file
+type Profile struct {
+	Name string
+	File string
+}
+
+// Profiles lists the profiles defined in configFile or, when that is empty,
+// in the default config locations, sorted by name. A name defined in several
+// files is listed once, for the file that takes precedence.
+func Profiles(configFile string) ([]Profile, error) {
+	files := []string{configFile}
+	if configFile == "" {
+		files = ConfigLocations()
+	}
+
+	var profiles []Profile
+	seen := map[string]bool{}
+	for _, path := range files {
+		if !isStructured(path) {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil && configFile == "" {
+			continue
+		}
+		doc, err := readDocument(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+		}
+		tables, err := profileTables(doc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %w", path, err)
+		}
+		for name := range tables {
+			if !seen[name] {
+				seen[name] = true
+				profiles = append(profiles, Profile{Name: name, File: path})
+			}
+		}
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// LoadProfile returns a profile's configuration from its file alone. Unlike
+// Load, it ignores environment variables and other files, so that profiles
+// can be used side by side. The profile's token command, if any, is run.
+func LoadProfile(p Profile) (*Config, error) {
+	values, _, err := readFile(p.File, p.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file %s: %w", p.File, err)
+	}
+
+	token, tokenCommand := values["ASANA_TOKEN"], values["ASANA_TOKEN_COMMAND"]
+	if token != "" && tokenCommand != "" {
+		return nil, fmt.Errorf("both token and token_command are set; use only one")
+	}
+	if tokenCommand != "" {
+		if token, err = runTokenCommand(tokenCommand); err != nil {
+			return nil, err
+		}
+	}
+	if token == "" {
+		return nil, fmt.Errorf("no token or token_command set")
+	}
+
+	return &Config{
+		Token:        token,
+		TokenCommand: tokenCommand,
+		Workspace:    values["ASANA_WORKSPACE"],
+		BaseURL:      values["ASANA_BASE_URL"],
+		TZ:           values["ASANA_TZ"],
+		AuditLog:     values["ASANA_AUDIT_LOG"],
+		Profile:      p.Name,
+		Files:        []string{p.File},
+
+		DefaultProject:  values["ASANA_DEFAULT_PROJECT"],
+		DefaultAssignee: values["ASANA_DEFAULT_ASSIGNEE"],
+	}, nil
+}
+
+// readFile reads a config file into environment variable values. The format
+// follows the extension: .toml, .yaml or .yml, and .env for anything else.
+// In TOML and YAML files, the keys of the named profile are layered over the
+// top-level ones; found reports whether the file has that profile.
+func readFile(path, profile string) (values map[string]string, found bool, err error) {
+	if !isStructured(path) {
+		values, err = godotenv.Read(path)
+		return values, false, err
+	}
+
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	values = map[string]string{}
+	if err := setValues(values, doc, ""); err != nil {
+		return nil, false, err
+	}
+	if profile == "" {
+		return values, false, nil
+	}
+
+	profiles, err := profileTables(doc)
+	if err != nil {
+		return nil, false, err
+	}
+	p, found := profiles[profile]
+	if found {
+		// A profile's token replaces a top-level token command and the
+		// other way around, rather than clashing with it
+		if _, ok := p["token"]; ok {
+			delete(values, "ASANA_TOKEN_COMMAND")
+		}
+		if _, ok := p["token_command"]; ok {
+			delete(values, "ASANA_TOKEN")
+		}
+		if err := setValues(values, p, "profiles."+profile+"."); err != nil {
+			return nil, false, err
+		}
+	}
+	return values, found, nil
+}
+
+// readDocument parses a TOML or YAML config file into nested tables
+func readDocument(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if strings.ToLower(filepath.Ext(path)) == ".toml" {
+		_, err = toml.Decode(string(data), &doc)
+	} else {
+		err = yaml.Unmarshal(data, &doc)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	return doc, nil
+}
+
+// profileTables returns the tables under a config file's profiles key
+func profileTables(doc map[string]interface{}) (map[string]map[string]interface{}, error) {
+	profiles := map[string]map[string]interface{}{}
+	raw, ok := doc["profiles"]
+	if !ok {
+		return profiles, nil
+	}
+	table, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("profiles must be a table of profiles")
+	}
+	for name, v := range table {
+		p, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("profiles.%s must be a table of settings", name)
+		}
+		profiles[name] = p
+	}
+	return profiles, nil
+}
+
+// setValues copies the settings in table into values under their
+// environment variable names. Unknown keys are errors, so that typos don't
+// silently fall back to defaults.
+func setValues(values map[string]string, table map[string]interface{}, prefix string) error {
+	for key, v := range table {
+		if prefix == "" && key == "profiles" {
+			continue
+		}
+		name, ok := fileKeys[key]
+		if !ok {
+			return fmt.Errorf("unknown setting %s%s (valid settings: %s)", prefix, key, strings.Join(settingNames(), ", "))
+		}
+		switch v := v.(type) {
+		case nil:
+		case string:
+			values[name] = v
+		case int, int64, uint64, float64, bool:
+			// A workspace GID is often written without quotes
+			values[name] = fmt.Sprint(v)
+		default:
+			return fmt.Errorf("%s%s must be a single value", prefix, key)
+		}
+	}
+	return nil
+}
+
+// settingNames returns the keys TOML and YAML config files accept, sorted
+func settingNames() []string {
+	names := make([]string, 0, len(fileKeys))
+	for k := range fileKeys {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -10,19 +10,43 @@ import (
 	"github.com/mauricejumelet/asana-cli/internal/config"
 )
 
-var version = "1.3.1"
+// Set by the release build (see .goreleaser.yaml)
+var (
+	version = "1.3.1"
+	commit  = ""
+	date    = ""
+)
 
 var CLI struct {
 	// Global flags
-	Config string `short:"c" help:"Path to config file (.env format)" type:"path"`
+	cmd.Globals
 
 	// Commands
-	Tasks       cmd.TasksCmd       `cmd:"" help:"Manage tasks"`
-	Projects    cmd.ProjectsCmd    `cmd:"" help:"Manage projects"`
-	Users       cmd.UsersCmd       `cmd:"" help:"Manage users"`
-	Attachments cmd.AttachmentsCmd `cmd:"" help:"Manage attachments"`
-	Summary     cmd.SummaryCmd     `cmd:"" help:"Show task summary and statistics"`
-	Configure   ConfigureCmd       `cmd:"" help:"Show configuration help"`
+	Tasks            cmd.TasksCmd            `cmd:"" help:"Manage tasks"`
+	Projects         cmd.ProjectsCmd         `cmd:"" help:"Manage projects"`
+	Tags             cmd.TagsCmd             `cmd:"" help:"Work with tags"`
+	Teams            cmd.TeamsCmd            `cmd:"" help:"List teams"`
+	ProjectTemplates cmd.ProjectTemplatesCmd `cmd:"" help:"List project templates"`
+	Users            cmd.UsersCmd            `cmd:"" help:"Manage users"`
+	Me               cmd.MeCmd               `cmd:"" help:"Show your own My Tasks list"`
+	Today            cmd.TodayCmd            `cmd:"" help:"Show your open tasks due today, overdue ones first"`
+	Week             cmd.WeekCmd             `cmd:"" help:"Show your open tasks due this week, overdue ones first"`
+	Workspaces       cmd.WorkspacesCmd       `cmd:"" help:"Manage workspaces"`
+	Auth             cmd.AuthCmd             `cmd:"" help:"Check authentication"`
+	Context          cmd.ContextCmd          `cmd:"" help:"Show which account, workspace and config are in use"`
+	Ping             cmd.PingCmd             `cmd:"" help:"Check connectivity, authentication and workspace access"`
+	Find             cmd.FindCmd             `cmd:"" help:"Find tasks, projects, users and tags by name"`
+	Attachments      cmd.AttachmentsCmd      `cmd:"" help:"Manage attachments"`
+	Sections         cmd.SectionsCmd         `cmd:"" help:"List, rename and reorder a project's sections"`
+	Stories          cmd.StoriesCmd          `cmd:"" help:"Pin and unpin comments"`
+	Summary          cmd.SummaryCmd          `cmd:"" help:"Show task summary and statistics"`
+	Export           cmd.ExportCmd           `cmd:"" help:"Export a project's tasks to JSON"`
+	Import           cmd.ImportCmd           `cmd:"" help:"Create tasks in bulk from a CSV or JSON file"`
+	Template         cmd.TemplateCmd         `cmd:"" help:"Create tasks from local templates"`
+	Cache            cmd.CacheCmd            `cmd:"" help:"Manage the response cache"`
+	ConfigCmd        cmd.ConfigCmd           `cmd:"" name:"config" help:"Create a config file"`
+	Configure        ConfigureCmd            `cmd:"" help:"Show configuration help"`
+	Version          cmd.VersionCmd          `cmd:"" help:"Show version and build details"`
 }
 
 type ConfigureCmd struct{}
@@ -45,30 +69,95 @@ func main() {
 		kong.Name("asana"),
 		kong.Description("A command-line interface for Asana (v"+version+")"),
 		kong.UsageOnError(),
+		kong.Bind(cmd.BuildInfo{Version: version, Commit: commit, Date: date}),
 		kong.ConfigureHelp(kong.HelpOptions{
 			Compact: true,
 		}),
 	)
 
+	if err := cmd.SetYAMLOutput(ctx, CLI.YAML); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Commands that don't need the API client
 	switch ctx.Command() {
-	case "configure":
-		err := ctx.Run()
+	case "configure", "cache clear", "template list", "config init", "version":
+		err := ctx.Run(&CLI.Globals)
 		ctx.FatalIfErrorf(err)
 		return
 	}
 
-	// Load configuration
-	cfg, err := config.Load(CLI.Config)
+	// auth check --all loads every profile itself
+	if ctx.Command() == "auth check" && CLI.Auth.Check.All {
+		err := ctx.Run(&CLI.Globals, (*api.Client)(nil))
+		ctx.FatalIfErrorf(err)
+		return
+	}
+
+	// Load configuration. The workspace is optional when given as a flag or
+	// for commands that only need a token.
+	requireWorkspace := CLI.Workspace == ""
+	switch ctx.Command() {
+	case "workspaces list", "workspaces get <workspace>", "auth check", "context", "ping":
+		requireWorkspace = false
+	}
+	cfg, err := config.Load(CLI.Config, CLI.Profile, CLI.TokenCommand, requireWorkspace)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	if CLI.BaseURL != "" {
+		cfg.BaseURL = CLI.BaseURL
+	}
+	if CLI.TZ != "" {
+		cfg.TZ = CLI.TZ
+	}
+	if err := cmd.SetTimeFormat(cfg.TZ, CLI.DateFormat); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create API client
 	client := api.NewClient(cfg)
+	if CLI.Verbose {
+		client.SetDebugOutput(os.Stderr)
+	}
+	if cfg.AuditLog != "" {
+		client.EnableAuditLog(cfg.AuditLog, ctx.Command())
+	}
+	client.SetMaxConcurrentRequests(CLI.MaxConcurrent)
+	if CLI.RetryOnConflict {
+		client.SetRetryOnConflict(true)
+	}
+	if CLI.CacheTTL != 0 {
+		cfg.CacheTTL = CLI.CacheTTL
+	} else if CLI.UseCache && cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = api.DefaultCacheTTL
+	}
+	if !CLI.NoCache && cfg.CacheTTL > 0 {
+		if dir, err := api.CacheDir(); err == nil {
+			client.EnableCache(dir, cfg.CacheTTL)
+		}
+	}
+
+	// --workspace beats both ASANA_WORKSPACE and the config file
+	if CLI.Workspace != "" {
+		workspace, err := cmd.ResolveWorkspace(client, CLI.Workspace)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", api.ExplainAuthError(err))
+			os.Exit(1)
+		}
+		client.SetWorkspace(workspace)
+	}
 
 	// Run the command with the client
-	err = ctx.Run(client)
-	ctx.FatalIfErrorf(err)
+	finish, err := cmd.RedirectOutput(CLI.Out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	err = finish(ctx.Run(client, &CLI.Globals, cfg))
+	ctx.FatalIfErrorf(api.ExplainAuthError(cmd.ExplainConflict(err, CLI.RetryOnConflict)))
 }